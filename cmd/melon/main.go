@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/gomelon/melon/data/generator"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const usage = `Usage: melon <command> [arguments]
+
+Commands:
+    gen [dir ...]    generate the repositories annotated by //melon:repository in the directories,
+                     the dir ends with /... includes the sub directories, default is the current directory
+`
+
+func main() {
+	flag.Usage = func() {
+		_, _ = fmt.Fprint(os.Stderr, usage)
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	switch flag.Arg(0) {
+	case "gen":
+		if err := gen(flag.Args()[1:]); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "melon: unknown command [%s]\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func gen(patterns []string) error {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	dirs, err := expandDirs(patterns)
+	if err != nil {
+		return err
+	}
+	g := generator.NewGenerator()
+	for _, dir := range dirs {
+		paths, err := g.Generate(dir)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+	}
+	return nil
+}
+
+// expandDirs expands the pattern ends with /... to the directory and its sub directories,
+// the hidden directories, the testdata and the vendor directories are skipped
+func expandDirs(patterns []string) (dirs []string, err error) {
+	for _, pattern := range patterns {
+		if !strings.HasSuffix(pattern, "/...") {
+			dirs = append(dirs, pattern)
+			continue
+		}
+		root := strings.TrimSuffix(pattern, "/...")
+		if len(root) == 0 {
+			root = "."
+		}
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		})
+		if err != nil {
+			return
+		}
+	}
+	return
+}
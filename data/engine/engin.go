@@ -1,13 +1,92 @@
 package engine
 
+import (
+	"errors"
+	"strings"
+)
+
 var Engines map[string]Engine = map[string]Engine{}
 
 type Engine interface {
 	Dialect() string
 	Escape(str string) string
 	BuildColumn(str string) string
-	BuildContains(str string) string
-	BuildStartsWith(str string) string
-	BuildEndsWith(str string) string
+	//BuildContains returns the condition that the column contains the string, case-insensitive if ignoreCase,
+	//the same below
+	BuildContains(column string, str string, ignoreCase bool) string
+	BuildStartsWith(column string, str string, ignoreCase bool) string
+	BuildEndsWith(column string, str string, ignoreCase bool) string
 	BuildLimit(offset, limit string) string
+	BuildIsTrue(column string) string
+	BuildIsFalse(column string) string
+	//BuildIsEmpty returns the condition that the column is NULL or empty,
+	//the collection column is stored as a JSON array, error if the engine does not support it
+	BuildIsEmpty(column string, collection bool) (string, error)
+	//BuildIsNotEmpty returns the condition that the column is neither NULL nor empty
+	BuildIsNotEmpty(column string, collection bool) (string, error)
+	//BuildMatches returns the condition that the column matches the regular expression,
+	//case-insensitive if ignoreCase, error if the engine does not support it
+	BuildMatches(column string, pattern string, ignoreCase bool) (string, error)
+	//BuildJSONPath returns the scalar value at the path of the JSON column as text
+	BuildJSONPath(column string, path []string) string
+	//BuildTop returns the TOP clause placed after SELECT, empty if the engine limit rows by BuildLimit only
+	BuildTop(limit string) string
+	//BuildDefaultOrderBy returns the ORDER BY clause used when BuildLimit requires one but the query has no sorts,
+	//empty if not required
+	BuildDefaultOrderBy() string
+	//BuildRowID returns the pseudo column identifying a row, it is used to limit the rows of DELETE by a subquery,
+	//empty if the engine supports DELETE with ORDER BY and LIMIT, or limits the rows by TOP in a common table expression
+	BuildRowID() string
+	//BuildUpsert returns the clause appended to INSERT to update the conflicting rows instead,
+	//the columns are escaped, error if the engine does not support upsert
+	BuildUpsert(conflictColumns []string, updateColumns []string) (string, error)
+	//SupportRowValue returns whether the engine compares row values, EX: (a, b) > (1, 2)
+	SupportRowValue() bool
+	//BuildSavepoint returns the statement creating the savepoint in the transaction
+	BuildSavepoint(name string) string
+	//BuildRollbackToSavepoint returns the statement rolling back the transaction to the savepoint
+	BuildRollbackToSavepoint(name string) string
+	//BuildReleaseSavepoint returns the statement releasing the savepoint, empty if the engine does not release it
+	BuildReleaseSavepoint(name string) string
+	//IsRetryable returns whether the transaction failed by the error can be retried, such as deadlock
+	//and serialization failure, the error is classified by the SQLSTATE, the error number or the message
+	IsRetryable(err error) bool
+	//BindType returns the placeholder style of bound arguments, one of sqlx.QUESTION, sqlx.DOLLAR, sqlx.NAMED, sqlx.AT
+	BindType() int
+}
+
+// lowerIfIgnoreCase lowers both sides of the case-insensitive comparison
+func lowerIfIgnoreCase(column string, str string, ignoreCase bool) (string, string) {
+	if !ignoreCase {
+		return column, str
+	}
+	return "LOWER(" + column + ")", "LOWER(" + str + ")"
+}
+
+// errorSQLState returns the SQLSTATE of the driver error, EX: github.com/jackc/pgx/v5/pgconn.PgError
+func errorSQLState(err error) string {
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		return stateErr.SQLState()
+	}
+	return ""
+}
+
+// errorNumber returns the error number of the driver error, EX: github.com/microsoft/go-mssqldb.Error
+func errorNumber(err error) int32 {
+	var numberErr interface{ SQLErrorNumber() int32 }
+	if errors.As(err, &numberErr) {
+		return numberErr.SQLErrorNumber()
+	}
+	return 0
+}
+
+func errorContainsAny(err error, substrs ...string) bool {
+	message := err.Error()
+	for _, substr := range substrs {
+		if strings.Contains(message, substr) {
+			return true
+		}
+	}
+	return false
 }
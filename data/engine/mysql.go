@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"github.com/gomelon/melon/third_party/sqlx"
 	"github.com/huandu/xstrings"
 	"strings"
 )
@@ -33,18 +34,111 @@ func (m *MySQL) BuildColumn(str string) string {
 	return xstrings.ToSnakeCase(str)
 }
 
-func (m *MySQL) BuildContains(str string) string {
-	return fmt.Sprintf("LIKE CONCAT('%%',%s,'%%')", str)
+func (m *MySQL) BuildContains(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE CONCAT('%%',%s,'%%')", column, str)
 }
 
-func (m *MySQL) BuildStartsWith(str string) string {
-	return fmt.Sprintf("LIKE CONCAT(%s,'%%')", str)
+func (m *MySQL) BuildStartsWith(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE CONCAT(%s,'%%')", column, str)
 }
 
-func (m *MySQL) BuildEndsWith(str string) string {
-	return fmt.Sprintf("LIKE CONCAT('%%',%s)", str)
+func (m *MySQL) BuildEndsWith(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE CONCAT('%%',%s)", column, str)
 }
 
 func (m *MySQL) BuildLimit(offset, limit string) string {
 	return fmt.Sprintf("LIMIT %s, %s", offset, limit)
 }
+
+func (m *MySQL) BuildIsTrue(column string) string {
+	return column
+}
+
+func (m *MySQL) BuildIsFalse(column string) string {
+	return "!" + column
+}
+
+// BuildIsEmpty the collection is stored as a JSON array
+func (m *MySQL) BuildIsEmpty(column string, collection bool) (string, error) {
+	if collection {
+		return fmt.Sprintf("%s IS NULL OR JSON_LENGTH(%s) = 0", column, column), nil
+	}
+	return fmt.Sprintf("%s IS NULL OR %s = ''", column, column), nil
+}
+
+func (m *MySQL) BuildIsNotEmpty(column string, collection bool) (string, error) {
+	if collection {
+		return fmt.Sprintf("%s IS NOT NULL AND JSON_LENGTH(%s) > 0", column, column), nil
+	}
+	return fmt.Sprintf("%s IS NOT NULL AND %s != ''", column, column), nil
+}
+
+// BuildMatches REGEXP follows the collation of the column, REGEXP_LIKE with the match type requires MySQL 8.0.4 or later
+func (m *MySQL) BuildMatches(column string, pattern string, ignoreCase bool) (string, error) {
+	if ignoreCase {
+		return fmt.Sprintf("REGEXP_LIKE(%s, %s, 'i')", column, pattern), nil
+	}
+	return fmt.Sprintf("%s REGEXP %s", column, pattern), nil
+}
+
+func (m *MySQL) BuildJSONPath(column string, path []string) string {
+	return fmt.Sprintf("%s->>'$.%s'", column, strings.Join(path, "."))
+}
+
+func (m *MySQL) BuildTop(limit string) string {
+	return ""
+}
+
+func (m *MySQL) BuildDefaultOrderBy() string {
+	return ""
+}
+
+func (m *MySQL) BuildRowID() string {
+	return ""
+}
+
+func (m *MySQL) BuildUpsert(conflictColumns []string, updateColumns []string) (string, error) {
+	//MySQL resolves the conflict by any unique key, so the conflict columns are only used if nothing to update
+	if len(updateColumns) == 0 {
+		if len(conflictColumns) == 0 {
+			return "", fmt.Errorf("build upsert fail: nothing to update")
+		}
+		updateColumns = conflictColumns[:1]
+	}
+	assignments := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		assignments = append(assignments, fmt.Sprintf("%s = VALUES(%s)", column, column))
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", "), nil
+}
+
+func (m *MySQL) SupportRowValue() bool {
+	return true
+}
+
+func (m *MySQL) BuildSavepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (m *MySQL) BuildRollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func (m *MySQL) BuildReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+func (m *MySQL) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	//1213 deadlock, 1205 lock wait timeout, the driver formats the error as "Error 1213 (40001): ..."
+	return errorSQLState(err) == "40001" || errorContainsAny(err, "Error 1213", "Error 1205")
+}
+
+func (m *MySQL) BindType() int {
+	return sqlx.QUESTION
+}
@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/gomelon/melon/third_party/sqlx"
+	"github.com/huandu/xstrings"
+	"strings"
+)
+
+func UseOracle() {
+	oracle := NewOracle()
+	Engines[oracle.Dialect()] = oracle
+}
+
+type Oracle struct {
+}
+
+func NewOracle() *Oracle {
+	return &Oracle{}
+}
+
+func (o *Oracle) Dialect() string {
+	return "oracle"
+}
+
+func (o *Oracle) Escape(str string) string {
+	if strings.HasPrefix(str, `"`) {
+		return str
+	}
+	return fmt.Sprintf(`"%s"`, str)
+}
+
+func (o *Oracle) BuildColumn(str string) string {
+	return xstrings.ToSnakeCase(str)
+}
+
+func (o *Oracle) BuildContains(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE '%%' || %s || '%%'", column, str)
+}
+
+func (o *Oracle) BuildStartsWith(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE %s || '%%'", column, str)
+}
+
+func (o *Oracle) BuildEndsWith(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE '%%' || %s", column, str)
+}
+
+// BuildLimit the row limiting clause requires Oracle 12c or later
+func (o *Oracle) BuildLimit(offset, limit string) string {
+	return fmt.Sprintf("OFFSET %s ROWS FETCH NEXT %s ROWS ONLY", offset, limit)
+}
+
+// BuildIsTrue Oracle has no boolean column type, booleans are stored as NUMBER(1) 0 (false) and 1 (true)
+func (o *Oracle) BuildIsTrue(column string) string {
+	return column + " = 1"
+}
+
+func (o *Oracle) BuildIsFalse(column string) string {
+	return column + " = 0"
+}
+
+// BuildIsEmpty Oracle treats the empty string as NULL, the collection is stored as a JSON array
+func (o *Oracle) BuildIsEmpty(column string, collection bool) (string, error) {
+	if collection {
+		return fmt.Sprintf("%s IS NULL OR NOT JSON_EXISTS(%s, '$[0]')", column, column), nil
+	}
+	return fmt.Sprintf("%s IS NULL", column), nil
+}
+
+func (o *Oracle) BuildIsNotEmpty(column string, collection bool) (string, error) {
+	if collection {
+		return fmt.Sprintf("%s IS NOT NULL AND JSON_EXISTS(%s, '$[0]')", column, column), nil
+	}
+	return fmt.Sprintf("%s IS NOT NULL", column), nil
+}
+
+func (o *Oracle) BuildMatches(column string, pattern string, ignoreCase bool) (string, error) {
+	if ignoreCase {
+		return fmt.Sprintf("REGEXP_LIKE(%s, %s, 'i')", column, pattern), nil
+	}
+	return fmt.Sprintf("REGEXP_LIKE(%s, %s)", column, pattern), nil
+}
+
+func (o *Oracle) BuildJSONPath(column string, path []string) string {
+	return fmt.Sprintf("JSON_VALUE(%s, '$.%s')", column, strings.Join(path, "."))
+}
+
+func (o *Oracle) BuildTop(limit string) string {
+	return ""
+}
+
+func (o *Oracle) BuildDefaultOrderBy() string {
+	return ""
+}
+
+func (o *Oracle) BuildRowID() string {
+	return "ROWID"
+}
+
+func (o *Oracle) BuildUpsert(conflictColumns []string, updateColumns []string) (string, error) {
+	return "", fmt.Errorf("build upsert fail: engine [%s] does not support upsert", o.Dialect())
+}
+
+func (o *Oracle) SupportRowValue() bool {
+	return false
+}
+
+func (o *Oracle) BuildSavepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (o *Oracle) BuildRollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func (o *Oracle) BuildReleaseSavepoint(name string) string {
+	//Oracle releases the savepoints on commit only
+	return ""
+}
+
+func (o *Oracle) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	//ORA-00060 deadlock detected, ORA-08177 can't serialize access
+	return errorContainsAny(err, "ORA-00060", "ORA-08177")
+}
+
+func (o *Oracle) BindType() int {
+	return sqlx.NAMED
+}
@@ -0,0 +1,159 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/gomelon/melon/third_party/sqlx"
+	"github.com/huandu/xstrings"
+	"strings"
+)
+
+func UsePostgreSQL() {
+	postgreSQL := NewPostgreSQL()
+	Engines[postgreSQL.Dialect()] = postgreSQL
+}
+
+type PostgreSQL struct {
+}
+
+func NewPostgreSQL() *PostgreSQL {
+	return &PostgreSQL{}
+}
+
+func (p *PostgreSQL) Dialect() string {
+	return "postgres"
+}
+
+func (p *PostgreSQL) Escape(str string) string {
+	if strings.HasPrefix(str, `"`) {
+		return str
+	}
+	return fmt.Sprintf(`"%s"`, str)
+}
+
+func (p *PostgreSQL) BuildColumn(str string) string {
+	return xstrings.ToSnakeCase(str)
+}
+
+func (p *PostgreSQL) BuildContains(column string, str string, ignoreCase bool) string {
+	if ignoreCase {
+		return fmt.Sprintf("%s ILIKE '%%' || %s || '%%'", column, str)
+	}
+	return fmt.Sprintf("%s LIKE '%%' || %s || '%%'", column, str)
+}
+
+func (p *PostgreSQL) BuildStartsWith(column string, str string, ignoreCase bool) string {
+	if ignoreCase {
+		return fmt.Sprintf("%s ILIKE %s || '%%'", column, str)
+	}
+	return fmt.Sprintf("%s LIKE %s || '%%'", column, str)
+}
+
+func (p *PostgreSQL) BuildEndsWith(column string, str string, ignoreCase bool) string {
+	if ignoreCase {
+		return fmt.Sprintf("%s ILIKE '%%' || %s", column, str)
+	}
+	return fmt.Sprintf("%s LIKE '%%' || %s", column, str)
+}
+
+func (p *PostgreSQL) BuildLimit(offset, limit string) string {
+	return fmt.Sprintf("LIMIT %s OFFSET %s", limit, offset)
+}
+
+func (p *PostgreSQL) BuildIsTrue(column string) string {
+	return column
+}
+
+func (p *PostgreSQL) BuildIsFalse(column string) string {
+	return "NOT " + column
+}
+
+// BuildIsEmpty the collection is stored as a JSON array, the column is cast to jsonb to accept json, jsonb and text
+func (p *PostgreSQL) BuildIsEmpty(column string, collection bool) (string, error) {
+	if collection {
+		return fmt.Sprintf("%s IS NULL OR jsonb_array_length(%s::jsonb) = 0", column, column), nil
+	}
+	return fmt.Sprintf("%s IS NULL OR %s = ''", column, column), nil
+}
+
+func (p *PostgreSQL) BuildIsNotEmpty(column string, collection bool) (string, error) {
+	if collection {
+		return fmt.Sprintf("%s IS NOT NULL AND jsonb_array_length(%s::jsonb) > 0", column, column), nil
+	}
+	return fmt.Sprintf("%s IS NOT NULL AND %s != ''", column, column), nil
+}
+
+func (p *PostgreSQL) BuildMatches(column string, pattern string, ignoreCase bool) (string, error) {
+	if ignoreCase {
+		return fmt.Sprintf("%s ~* %s", column, pattern), nil
+	}
+	return fmt.Sprintf("%s ~ %s", column, pattern), nil
+}
+
+func (p *PostgreSQL) BuildJSONPath(column string, path []string) string {
+	if len(path) == 1 {
+		return fmt.Sprintf("%s->>'%s'", column, path[0])
+	}
+	return fmt.Sprintf("%s#>>'{%s}'", column, strings.Join(path, ","))
+}
+
+func (p *PostgreSQL) BuildTop(limit string) string {
+	return ""
+}
+
+func (p *PostgreSQL) BuildDefaultOrderBy() string {
+	return ""
+}
+
+func (p *PostgreSQL) BuildRowID() string {
+	return "ctid"
+}
+
+func (p *PostgreSQL) BuildUpsert(conflictColumns []string, updateColumns []string) (string, error) {
+	return buildOnConflict(conflictColumns, updateColumns)
+}
+
+func (p *PostgreSQL) SupportRowValue() bool {
+	return true
+}
+
+func (p *PostgreSQL) BuildSavepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (p *PostgreSQL) BuildRollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func (p *PostgreSQL) BuildReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+func (p *PostgreSQL) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	//40001 serialization failure, 40P01 deadlock detected
+	state := errorSQLState(err)
+	return state == "40001" || state == "40P01" ||
+		errorContainsAny(err, "SQLSTATE 40001", "SQLSTATE 40P01", "deadlock detected", "could not serialize access")
+}
+
+func (p *PostgreSQL) BindType() int {
+	return sqlx.DOLLAR
+}
+
+// buildOnConflict builds the ON CONFLICT clause shared by PostgreSQL and SQLite
+func buildOnConflict(conflictColumns []string, updateColumns []string) (string, error) {
+	if len(conflictColumns) == 0 {
+		return "", fmt.Errorf("build upsert fail: the conflict columns are required")
+	}
+	onConflict := "ON CONFLICT (" + strings.Join(conflictColumns, ", ") + ")"
+	if len(updateColumns) == 0 {
+		return onConflict + " DO NOTHING", nil
+	}
+	assignments := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		assignments = append(assignments, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+	}
+	return onConflict + " DO UPDATE SET " + strings.Join(assignments, ", "), nil
+}
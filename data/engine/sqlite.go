@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/gomelon/melon/third_party/sqlx"
+	"github.com/huandu/xstrings"
+	"regexp"
+	"strings"
+)
+
+func UseSQLite() {
+	sqlite := NewSQLite()
+	Engines[sqlite.Dialect()] = sqlite
+}
+
+type SQLite struct {
+}
+
+func NewSQLite() *SQLite {
+	return &SQLite{}
+}
+
+func (s *SQLite) Dialect() string {
+	return "sqlite3"
+}
+
+func (s *SQLite) Escape(str string) string {
+	if strings.HasPrefix(str, `"`) {
+		return str
+	}
+	return fmt.Sprintf(`"%s"`, str)
+}
+
+func (s *SQLite) BuildColumn(str string) string {
+	return xstrings.ToSnakeCase(str)
+}
+
+func (s *SQLite) BuildContains(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE '%%' || %s || '%%'", column, str)
+}
+
+func (s *SQLite) BuildStartsWith(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE %s || '%%'", column, str)
+}
+
+func (s *SQLite) BuildEndsWith(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE '%%' || %s", column, str)
+}
+
+func (s *SQLite) BuildLimit(offset, limit string) string {
+	return fmt.Sprintf("LIMIT %s OFFSET %s", limit, offset)
+}
+
+// BuildIsTrue SQLite has no boolean type, booleans are stored as integer 0 (false) and 1 (true)
+func (s *SQLite) BuildIsTrue(column string) string {
+	return column + " = 1"
+}
+
+func (s *SQLite) BuildIsFalse(column string) string {
+	return column + " = 0"
+}
+
+// BuildIsEmpty the collection is stored as a JSON array, it requires the JSON functions of SQLite 3.38 or later
+// or the JSON1 extension
+func (s *SQLite) BuildIsEmpty(column string, collection bool) (string, error) {
+	if collection {
+		return fmt.Sprintf("%s IS NULL OR json_array_length(%s) = 0", column, column), nil
+	}
+	return fmt.Sprintf("%s IS NULL OR %s = ''", column, column), nil
+}
+
+func (s *SQLite) BuildIsNotEmpty(column string, collection bool) (string, error) {
+	if collection {
+		return fmt.Sprintf("%s IS NOT NULL AND json_array_length(%s) > 0", column, column), nil
+	}
+	return fmt.Sprintf("%s IS NOT NULL AND %s != ''", column, column), nil
+}
+
+// BuildMatches SQLite has no built-in regexp function, REGEXP calls the user function regexp(pattern, str),
+// it should be registered to the connection, EX: SQLiteRegexp, the pattern is prefixed by the flag (?i) to ignore case
+func (s *SQLite) BuildMatches(column string, pattern string, ignoreCase bool) (string, error) {
+	if ignoreCase {
+		return fmt.Sprintf("%s REGEXP '(?i)' || %s", column, pattern), nil
+	}
+	return fmt.Sprintf("%s REGEXP %s", column, pattern), nil
+}
+
+// BuildJSONPath the ->> operator requires SQLite 3.38 or later
+func (s *SQLite) BuildJSONPath(column string, path []string) string {
+	return fmt.Sprintf("%s->>'$.%s'", column, strings.Join(path, "."))
+}
+
+func (s *SQLite) BuildTop(limit string) string {
+	return ""
+}
+
+func (s *SQLite) BuildDefaultOrderBy() string {
+	return ""
+}
+
+func (s *SQLite) BuildRowID() string {
+	return "rowid"
+}
+
+func (s *SQLite) BuildUpsert(conflictColumns []string, updateColumns []string) (string, error) {
+	return buildOnConflict(conflictColumns, updateColumns)
+}
+
+func (s *SQLite) SupportRowValue() bool {
+	return true
+}
+
+func (s *SQLite) BuildSavepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (s *SQLite) BuildRollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func (s *SQLite) BuildReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+func (s *SQLite) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	//SQLITE_BUSY and SQLITE_LOCKED
+	return errorContainsAny(err, "database is locked", "database table is locked", "SQLITE_BUSY")
+}
+
+func (s *SQLite) BindType() int {
+	return sqlx.QUESTION
+}
+
+// SQLiteRegexp implements the user function regexp(pattern, str) used by REGEXP,
+// register it to the connection of the driver, EX: conn.RegisterFunc("regexp", engine.SQLiteRegexp, true)
+func SQLiteRegexp(pattern string, str string) (bool, error) {
+	return regexp.MatchString(pattern, str)
+}
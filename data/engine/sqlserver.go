@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/gomelon/melon/third_party/sqlx"
+	"github.com/huandu/xstrings"
+	"strings"
+)
+
+func UseSQLServer() {
+	sqlServer := NewSQLServer()
+	Engines[sqlServer.Dialect()] = sqlServer
+}
+
+type SQLServer struct {
+}
+
+func NewSQLServer() *SQLServer {
+	return &SQLServer{}
+}
+
+func (s *SQLServer) Dialect() string {
+	return "sqlserver"
+}
+
+func (s *SQLServer) Escape(str string) string {
+	if strings.HasPrefix(str, "[") {
+		return str
+	}
+	return fmt.Sprintf("[%s]", str)
+}
+
+func (s *SQLServer) BuildColumn(str string) string {
+	return xstrings.ToSnakeCase(str)
+}
+
+func (s *SQLServer) BuildContains(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE '%%' + %s + '%%'", column, str)
+}
+
+func (s *SQLServer) BuildStartsWith(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE %s + '%%'", column, str)
+}
+
+func (s *SQLServer) BuildEndsWith(column string, str string, ignoreCase bool) string {
+	column, str = lowerIfIgnoreCase(column, str, ignoreCase)
+	return fmt.Sprintf("%s LIKE '%%' + %s", column, str)
+}
+
+func (s *SQLServer) BuildLimit(offset, limit string) string {
+	return fmt.Sprintf("OFFSET %s ROWS FETCH NEXT %s ROWS ONLY", offset, limit)
+}
+
+// BuildIsTrue SQL Server stores booleans as bit 0 (false) and 1 (true)
+func (s *SQLServer) BuildIsTrue(column string) string {
+	return column + " = 1"
+}
+
+func (s *SQLServer) BuildIsFalse(column string) string {
+	return column + " = 0"
+}
+
+// BuildIsEmpty the collection is stored as a JSON array, it requires SQL Server 2016 or later
+func (s *SQLServer) BuildIsEmpty(column string, collection bool) (string, error) {
+	if collection {
+		return fmt.Sprintf("%s IS NULL OR NOT EXISTS (SELECT 1 FROM OPENJSON(%s))", column, column), nil
+	}
+	return fmt.Sprintf("%s IS NULL OR %s = ''", column, column), nil
+}
+
+func (s *SQLServer) BuildIsNotEmpty(column string, collection bool) (string, error) {
+	if collection {
+		return fmt.Sprintf("%s IS NOT NULL AND EXISTS (SELECT 1 FROM OPENJSON(%s))", column, column), nil
+	}
+	return fmt.Sprintf("%s IS NOT NULL AND %s != ''", column, column), nil
+}
+
+func (s *SQLServer) BuildMatches(column string, pattern string, ignoreCase bool) (string, error) {
+	return "", fmt.Errorf("build matches fail: engine [%s] does not support regular expression", s.Dialect())
+}
+
+func (s *SQLServer) BuildJSONPath(column string, path []string) string {
+	return fmt.Sprintf("JSON_VALUE(%s, '$.%s')", column, strings.Join(path, "."))
+}
+
+func (s *SQLServer) BuildTop(limit string) string {
+	return fmt.Sprintf("TOP %s", limit)
+}
+
+// BuildDefaultOrderBy OFFSET FETCH is part of the ORDER BY clause in SQL Server, it can not be used alone
+func (s *SQLServer) BuildDefaultOrderBy() string {
+	return "ORDER BY (SELECT NULL)"
+}
+
+func (s *SQLServer) BuildRowID() string {
+	return ""
+}
+
+func (s *SQLServer) BuildUpsert(conflictColumns []string, updateColumns []string) (string, error) {
+	return "", fmt.Errorf("build upsert fail: engine [%s] does not support upsert", s.Dialect())
+}
+
+func (s *SQLServer) SupportRowValue() bool {
+	return false
+}
+
+func (s *SQLServer) BuildSavepoint(name string) string {
+	return "SAVE TRANSACTION " + name
+}
+
+func (s *SQLServer) BuildRollbackToSavepoint(name string) string {
+	return "ROLLBACK TRANSACTION " + name
+}
+
+func (s *SQLServer) BuildReleaseSavepoint(name string) string {
+	//SQLServer releases the savepoints on commit only
+	return ""
+}
+
+func (s *SQLServer) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	//1205 deadlock victim, 3960 snapshot isolation update conflict
+	number := errorNumber(err)
+	return number == 1205 || number == 3960 || errorContainsAny(err, "deadlock victim")
+}
+
+func (s *SQLServer) BindType() int {
+	return sqlx.AT
+}
@@ -0,0 +1,95 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gomelon/melon/data/query"
+)
+
+// FindOne runs the Find query and maps the first row, returns sql.ErrNoRows if there is no row
+func FindOne[T any](ctx context.Context, executor SQLExecutor, translator query.Translator, q *query.Query,
+	mapper RowMapper[T]) (result T, err error) {
+
+	statement, err := translate(ctx, translator.TranslateFind, q)
+	if err != nil {
+		return
+	}
+	rows, err := executor.QueryContext(ctx, statement.SQL(), statement.Args()...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err = rows.Err(); err == nil {
+			err = sql.ErrNoRows
+		}
+		return
+	}
+	return mapper(rows)
+}
+
+// Exists runs the Exists query, returns true if any row matches
+func Exists(ctx context.Context, executor SQLExecutor, translator query.Translator, q *query.Query,
+) (exists bool, err error) {
+
+	statement, err := translate(ctx, translator.TranslateExists, q)
+	if err != nil {
+		return
+	}
+	rows, err := executor.QueryContext(ctx, statement.SQL(), statement.Args()...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	exists = rows.Next()
+	err = rows.Err()
+	return
+}
+
+// QueryValue runs the query selecting a single value, such as Sum, Avg, Min and Max,
+// use sql.Null* as T if the value may be NULL
+func QueryValue[T any](ctx context.Context, executor SQLExecutor, translator query.Translator, q *query.Query,
+) (value T, err error) {
+
+	err = scanValue(ctx, executor, translator, q, &value)
+	return
+}
+
+func scanValue(ctx context.Context, executor SQLExecutor, translator query.Translator, q *query.Query,
+	dest any) error {
+
+	statement, err := translate(ctx, translator.Translate, q)
+	if err != nil {
+		return err
+	}
+	return executor.QueryRowContext(ctx, statement.SQL(), statement.Args()...).Scan(dest)
+}
+
+// Exec runs the query returns no rows, such as Delete, Update, Insert and Save, returns the number of rows affected
+func Exec(ctx context.Context, executor SQLExecutor, translator query.Translator, q *query.Query,
+) (rowsAffected int64, err error) {
+
+	statement, err := translate(ctx, translator.Translate, q)
+	if err != nil {
+		return
+	}
+	result, err := executor.ExecContext(ctx, statement.SQL(), statement.Args()...)
+	if err != nil {
+		return
+	}
+	return result.RowsAffected()
+}
+
+func translate(ctx context.Context, translateFunc func(context.Context, *query.Query) (*query.Statement, error),
+	q *query.Query) (statement *query.Statement, err error) {
+
+	statement, err = translateFunc(ctx, q)
+	if err != nil {
+		return
+	}
+	if len(statement.NamedArgs()) > 0 {
+		err = fmt.Errorf("execute query fail: named args %v must be bound before executing", statement.NamedArgs())
+	}
+	return
+}
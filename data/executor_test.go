@@ -0,0 +1,73 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/gomelon/melon/data/engine"
+	"github.com/gomelon/melon/data/query"
+	"reflect"
+	"testing"
+)
+
+func TestExecutor(t *testing.T) {
+	fake := newFakeDB()
+	fake.returns("SELECT * FROM `user` WHERE (`id` = ?)", []string{"id", "name"})
+	fake.returns("SELECT 1 AS X FROM `user` WHERE (`email` = ?) LIMIT 0, 1", []string{"1"}, []driver.Value{int64(1)})
+	fake.returns("SELECT COALESCE(SUM(`balance`), 0) AS X FROM `user`", []string{"X"}, []driver.Value{2.5})
+	db := fake.open()
+	defer db.Close()
+
+	ctx := context.Background()
+	translator := query.NewRDBTranslator(engine.NewMySQL())
+	parser := NewRuleParser()
+	table := query.WithTable(query.NewTable("user"))
+	mapper := func(rows *sql.Rows) (name string, err error) {
+		var id int64
+		err = rows.Scan(&id, &name)
+		return
+	}
+
+	q, _ := parser.MustParse("FindById", table).Bind(int64(1))
+	_, err := FindOne(ctx, db, translator, q, mapper)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("FindOne() error = %v, want %v", err, sql.ErrNoRows)
+	}
+
+	q, _ = parser.MustParse("ExistsByEmail", table).Bind("lily@example.com")
+	exists, err := Exists(ctx, db, translator, q)
+	if err != nil || !exists {
+		t.Errorf("Exists() = %v, error = %v, want true", exists, err)
+	}
+
+	sum, err := QueryValue[float64](ctx, db, translator, parser.MustParse("SumBalance", table))
+	if err != nil || sum != 2.5 {
+		t.Errorf("QueryValue() = %v, error = %v, want 2.5", sum, err)
+	}
+
+	q, _ = parser.MustParse("UpdateStatusById", table).Bind(1, int64(1))
+	rowsAffected, err := Exec(ctx, db, translator, q)
+	if err != nil || rowsAffected != 1 {
+		t.Errorf("Exec() = %v, error = %v, want 1", rowsAffected, err)
+	}
+
+	named := parser.MustParse("FindById", table).With(query.WithFilterGroup(
+		query.NewFilterGroupWithFilters([]*query.Filter{
+			query.NewFilter("Id", query.PredicateIs, query.WithFilterNamedArgs("id")),
+		}, query.LogicOperatorAnd),
+	))
+	if _, err = FindOne(ctx, db, translator, named, mapper); err == nil {
+		t.Errorf("FindOne() expected error for the named args")
+	}
+
+	wantExecuted := []string{
+		"SELECT * FROM `user` WHERE (`id` = ?)",
+		"SELECT 1 AS X FROM `user` WHERE (`email` = ?) LIMIT 0, 1",
+		"SELECT COALESCE(SUM(`balance`), 0) AS X FROM `user`",
+		"UPDATE `user` SET `status` = ? WHERE (`id` = ?)",
+	}
+	if executed := fake.log(); !reflect.DeepEqual(executed, wantExecuted) {
+		t.Errorf("executed \nactual = %v, \nexpect = %v", executed, wantExecuted)
+	}
+}
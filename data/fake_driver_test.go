@@ -0,0 +1,144 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// fakeDB is an in-memory database/sql driver for tests,
+// the queries return the rows registered by the SQL and the executed SQLs are logged in order
+type fakeDB struct {
+	mu       sync.Mutex
+	rows     map[string]*fakeRows
+	errs     map[string][]error
+	executed []string
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{rows: map[string]*fakeRows{}, errs: map[string][]error{}}
+}
+
+func (f *fakeDB) open() *sql.DB {
+	return sql.OpenDB(f)
+}
+
+// returns registers the rows of the query
+func (f *fakeDB) returns(query string, columns []string, values ...[]driver.Value) {
+	f.rows[query] = &fakeRows{columns: columns, values: values}
+}
+
+// fails registers the errors returned by the query or statement in turn
+func (f *fakeDB) fails(query string, errs ...error) {
+	f.errs[query] = errs
+}
+
+func (f *fakeDB) log() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.executed...)
+}
+
+func (f *fakeDB) execute(query string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.executed = append(f.executed, query)
+	if errs := f.errs[query]; len(errs) > 0 {
+		f.errs[query] = errs[1:]
+		return errs[0]
+	}
+	return nil
+}
+
+func (f *fakeDB) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeConn{db: f}, nil
+}
+
+func (f *fakeDB) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) {
+	return nil, fmt.Errorf("fake driver opens by connector only")
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("fake driver does not prepare")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.db.execute("BEGIN"); err != nil {
+		return nil, err
+	}
+	return &fakeTx{db: c.db}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.db.execute(query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.db.execute(query); err != nil {
+		return nil, err
+	}
+	rows, ok := c.db.rows[query]
+	if !ok {
+		return nil, fmt.Errorf("fake driver has no rows of query [%s]", query)
+	}
+	return &fakeRows{columns: rows.columns, values: rows.values}, nil
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (t *fakeTx) Commit() error {
+	return t.db.execute("COMMIT")
+}
+
+func (t *fakeTx) Rollback() error {
+	return t.db.execute("ROLLBACK")
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	index   int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.index >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.index])
+	r.index++
+	return nil
+}
@@ -0,0 +1,641 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/gomelon/melon/data"
+	"github.com/gomelon/melon/data/engine"
+	"github.com/gomelon/melon/data/query"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Annotation marks the interface to generate the repository, EX:
+	//   //melon:repository table=user dialect=mysql key=Id sorts=Name,CreatedAt
+	// table is required, dialect is one of mysql(default), postgres, sqlite3, sqlserver and oracle,
+	// key is the conflict fields of Save(default Id), sorts is the fields allowed by the dynamic sorts
+	Annotation = "//melon:repository"
+	// FileSuffix is the suffix of the generated file, EX: user.go generates user_melon.go
+	FileSuffix = "_melon.go"
+	// TypeSuffix is the suffix of the generated type, EX: UserRepository generates UserRepositoryMelon
+	TypeSuffix = "Melon"
+)
+
+var dialects = map[string]struct {
+	newFunc string
+	engin   engine.Engine
+}{
+	"mysql":     {newFunc: "NewMySQL", engin: engine.NewMySQL()},
+	"postgres":  {newFunc: "NewPostgreSQL", engin: engine.NewPostgreSQL()},
+	"sqlite3":   {newFunc: "NewSQLite", engin: engine.NewSQLite()},
+	"sqlserver": {newFunc: "NewSQLServer", engin: engine.NewSQLServer()},
+	"oracle":    {newFunc: "NewOracle", engin: engine.NewOracle()},
+}
+
+// Generator generates the implementations of the repository interfaces from their method names
+type Generator struct {
+	parser *data.RuleParser
+}
+
+func NewGenerator() *Generator {
+	return &Generator{parser: data.NewRuleParser()}
+}
+
+// Generate scans the go files of the directory, writes a *_melon.go for each file has annotated interfaces,
+// returns the paths of the written files
+func (g *Generator) Generate(dir string) (paths []string, err error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		name := info.Name()
+		return !strings.HasSuffix(name, "_test.go") && !strings.HasSuffix(name, FileSuffix)
+	}, parser.ParseComments)
+	if err != nil {
+		return
+	}
+	pkgNames := make([]string, 0, len(pkgs))
+	for pkgName := range pkgs {
+		pkgNames = append(pkgNames, pkgName)
+	}
+	sort.Strings(pkgNames)
+	for _, pkgName := range pkgNames {
+		fileNames := make([]string, 0, len(pkgs[pkgName].Files))
+		for fileName := range pkgs[pkgName].Files {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
+		pkgFiles := make([]*ast.File, 0, len(fileNames))
+		for _, fileName := range fileNames {
+			pkgFiles = append(pkgFiles, pkgs[pkgName].Files[fileName])
+		}
+		for _, fileName := range fileNames {
+			var src []byte
+			src, err = g.GenerateFile(fset, pkgs[pkgName].Files[fileName], pkgFiles...)
+			if err != nil {
+				return
+			}
+			if src == nil {
+				continue
+			}
+			path := strings.TrimSuffix(fileName, ".go") + FileSuffix
+			if err = os.WriteFile(path, src, 0644); err != nil {
+				return
+			}
+			paths = append(paths, path)
+		}
+	}
+	return
+}
+
+// GenerateFile returns the gofmt'd source implementing the annotated interfaces of the file,
+// returns nil if the file has no annotated interface.
+// The rows are mapped by the generated mappers, the structs of the results are looked up in the file and the pkgFiles,
+// see repository.mapperOf
+func (g *Generator) GenerateFile(fset *token.FileSet, file *ast.File, pkgFiles ...*ast.File) ([]byte, error) {
+	types := typesOf(append([]*ast.File{file}, pkgFiles...))
+	var repositories []*repository
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			interfaceType, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				continue
+			}
+			doc := typeSpec.Doc
+			if doc == nil && len(genDecl.Specs) == 1 {
+				doc = genDecl.Doc
+			}
+			options, ok := annotationOf(doc)
+			if !ok {
+				continue
+			}
+			repo, err := g.parseRepository(fset, typeSpec.Name.Name, interfaceType, options)
+			if err == nil {
+				err = repo.parseMappers(types)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("generate %s fail: %w", fset.Position(typeSpec.Pos()), err)
+			}
+			repositories = append(repositories, repo)
+		}
+	}
+	if len(repositories) == 0 {
+		return nil, nil
+	}
+
+	w := &writer{}
+	w.line("// Code generated by melon gen. DO NOT EDIT.")
+	w.line("")
+	w.line("package %s", file.Name.Name)
+	w.line("")
+	w.line("import (")
+	for _, importSpec := range importsOf(file, repositories) {
+		w.line("%s", importSpec)
+	}
+	w.line(")")
+	for _, repo := range repositories {
+		repo.write(w)
+	}
+	src, err := format.Source(w.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generate %s fail: %w", fset.Position(file.Pos()), err)
+	}
+	return src, nil
+}
+
+// annotationOf returns the options of the annotation, EX: table=user dialect=mysql
+func annotationOf(doc *ast.CommentGroup) (options map[string]string, ok bool) {
+	if doc == nil {
+		return
+	}
+	for _, comment := range doc.List {
+		if !strings.HasPrefix(comment.Text, Annotation) {
+			continue
+		}
+		options = map[string]string{}
+		for _, option := range strings.Fields(strings.TrimPrefix(comment.Text, Annotation)) {
+			key, value, _ := strings.Cut(option, "=")
+			options[key] = value
+		}
+		return options, true
+	}
+	return
+}
+
+type methodKind int
+
+const (
+	methodFindAll methodKind = iota
+	methodFindOne
+	methodFindPage
+	methodCount
+	methodExists
+	methodAggregate
+	methodFindGroups
+	methodExec
+	methodInsert
+)
+
+type repository struct {
+	name       string
+	table      string
+	schema     string
+	dialect    string
+	keys       []string
+	sortFields []string
+	methods    []*method
+	mappers    []*mapper
+	scans      []*structScan
+}
+
+type method struct {
+	name     string
+	query    *query.Query
+	kind     methodKind
+	ctx      string
+	params   []*param
+	values   []*param
+	pager    *param
+	sortSpec *param
+	results  []string
+	elemType string
+	mapper   *mapper
+}
+
+type param struct {
+	name string
+	typ  string
+}
+
+// reservedNames are the names used in the generated method body
+var reservedNames = map[string]bool{"r": true, "q": true, "result": true, "err": true, "insertion": true}
+
+func (g *Generator) parseRepository(fset *token.FileSet, name string, interfaceType *ast.InterfaceType,
+	options map[string]string) (repo *repository, err error) {
+
+	repo = &repository{
+		name:    name,
+		table:   options["table"],
+		schema:  options["schema"],
+		dialect: options["dialect"],
+		keys:    []string{"Id"},
+	}
+	if len(repo.table) == 0 {
+		return nil, fmt.Errorf("repository %s must has the table option", name)
+	}
+	if len(repo.dialect) == 0 {
+		repo.dialect = "mysql"
+	}
+	dialect, ok := dialects[repo.dialect]
+	if !ok {
+		return nil, fmt.Errorf("repository %s has unsupported dialect [%s]", name, repo.dialect)
+	}
+	if keys := options["key"]; len(keys) > 0 {
+		repo.keys = strings.Split(keys, ",")
+	}
+	if sortFields := options["sorts"]; len(sortFields) > 0 {
+		repo.sortFields = strings.Split(sortFields, ",")
+	}
+
+	translator := query.NewRDBTranslator(dialect.engin)
+	for _, field := range interfaceType.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			return nil, fmt.Errorf("repository %s can not embed interface %s", name, exprString(fset, field.Type))
+		}
+		for _, methodName := range field.Names {
+			var m *method
+			m, err = g.parseMethod(fset, methodName.Name, funcType)
+			if err != nil {
+				return nil, fmt.Errorf("method %s.%s: %w", name, methodName.Name, err)
+			}
+			if err = validateTranslation(translator, m); err != nil {
+				return nil, fmt.Errorf("method %s.%s: %w", name, methodName.Name, err)
+			}
+			repo.methods = append(repo.methods, m)
+		}
+	}
+	return
+}
+
+func (g *Generator) parseMethod(fset *token.FileSet, name string, funcType *ast.FuncType) (m *method, err error) {
+	q, err := g.parser.Parse(name)
+	if err != nil {
+		return
+	}
+	m = &method{name: name, query: q}
+
+	for _, field := range funcType.Params.List {
+		typ := exprString(fset, field.Type)
+		if _, ok := field.Type.(*ast.Ellipsis); ok {
+			return nil, fmt.Errorf("variadic parameter %s is unsupported", typ)
+		}
+		if len(field.Names) == 0 {
+			m.params = append(m.params, &param{name: "arg" + strconv.Itoa(len(m.params)), typ: typ})
+			continue
+		}
+		for _, paramName := range field.Names {
+			if reservedNames[paramName.Name] {
+				return nil, fmt.Errorf("parameter name [%s] is reserved", paramName.Name)
+			}
+			m.params = append(m.params, &param{name: paramName.Name, typ: typ})
+		}
+	}
+	if len(m.params) == 0 || m.params[0].typ != "context.Context" {
+		return nil, fmt.Errorf("the first parameter must be context.Context")
+	}
+	if m.params[0].name == "_" || strings.HasPrefix(m.params[0].name, "arg") {
+		m.params[0].name = "ctx"
+	}
+	m.ctx = m.params[0].name
+	for _, p := range m.params[1:] {
+		switch p.typ {
+		case "query.Pager", "*query.PageRequest", "*query.KeysetRequest":
+			m.pager = p
+		case "*query.SortSpec", "[]*query.Sort":
+			m.sortSpec = p
+		default:
+			if m.pager != nil || m.sortSpec != nil {
+				return nil, fmt.Errorf("parameter %s must precede the pager and the sort parameters", p.name)
+			}
+			m.values = append(m.values, p)
+		}
+	}
+
+	if funcType.Results != nil {
+		for _, field := range funcType.Results.List {
+			typ := exprString(fset, field.Type)
+			for i := 0; i < len(field.Names) || i == 0; i++ {
+				m.results = append(m.results, typ)
+			}
+		}
+	}
+	if len(m.results) == 0 || m.results[len(m.results)-1] != "error" {
+		return nil, fmt.Errorf("the last result must be error")
+	}
+
+	if err = m.parseKind(); err != nil {
+		return nil, err
+	}
+	return m, m.validateParams()
+}
+
+func (m *method) parseKind() error {
+	subject := m.query.Subject()
+	results := m.results[:len(m.results)-1]
+	switch {
+	case subject == query.SubjectFind:
+		if len(results) != 1 {
+			return fmt.Errorf("find must returns the result and error")
+		}
+		switch result := results[0]; {
+		case strings.HasPrefix(result, "[]") && result != "[]byte":
+			m.kind = methodFindAll
+			m.elemType = strings.TrimPrefix(result, "[]")
+		case strings.HasPrefix(result, "*query.Page[") && strings.HasSuffix(result, "]"):
+			m.kind = methodFindPage
+			m.elemType = strings.TrimSuffix(strings.TrimPrefix(result, "*query.Page["), "]")
+		default:
+			m.kind = methodFindOne
+			m.elemType = result
+		}
+	case len(m.query.GroupBy()) > 0 && (subject == query.SubjectCount || subject.IsAggregate()):
+		m.kind = methodFindGroups
+		if len(results) != 1 || !strings.HasPrefix(results[0], "[]") || results[0] == "[]byte" {
+			return fmt.Errorf("grouped %s must returns the slice of the groups and error",
+				strings.ToLower(subject.Name()))
+		}
+		m.elemType = strings.TrimPrefix(results[0], "[]")
+	case subject == query.SubjectCount:
+		m.kind = methodCount
+		if len(results) != 1 || results[0] != "int64" {
+			return fmt.Errorf("count must returns int64 and error")
+		}
+	case subject == query.SubjectExists:
+		m.kind = methodExists
+		if len(results) != 1 || results[0] != "bool" {
+			return fmt.Errorf("exists must returns bool and error")
+		}
+	case subject.IsAggregate():
+		m.kind = methodAggregate
+		if len(results) != 1 {
+			return fmt.Errorf("%s must returns the result and error", strings.ToLower(subject.Name()))
+		}
+		m.elemType = results[0]
+	default:
+		m.kind = methodExec
+		if subject.Insertable() {
+			m.kind = methodInsert
+		}
+		if len(results) > 1 || len(results) == 1 && results[0] != "int64" {
+			return fmt.Errorf("%s must returns the rows affected of int64 and error, or error only",
+				strings.ToLower(subject.Name()))
+		}
+	}
+	return nil
+}
+
+// validateParams the number of the value parameters must match the filters and the assignments
+func (m *method) validateParams() error {
+	if m.kind != methodFindAll && m.kind != methodFindOne && m.kind != methodFindPage {
+		if m.pager != nil || m.sortSpec != nil {
+			return fmt.Errorf("the pager and the sort parameters are only for find")
+		}
+	}
+	if m.kind == methodFindPage && m.pager == nil {
+		return fmt.Errorf("find page must has the pager parameter")
+	}
+	if m.kind == methodInsert {
+		if len(m.values) != 1 {
+			return fmt.Errorf("%s must has one parameter of the entity or the entities",
+				strings.ToLower(m.query.Subject().Name()))
+		}
+		return nil
+	}
+	numValue := len(m.query.Assignments())
+	if m.query.FilterGroup() != nil {
+		numValue += m.query.FilterGroup().NumValue()
+	}
+	if len(m.values) != numValue {
+		return fmt.Errorf("expected %d value parameters, but actual %d value parameters", numValue, len(m.values))
+	}
+	return nil
+}
+
+// validateTranslation translates the query to find the errors on generating instead of on executing
+func validateTranslation(translator query.Translator, m *method) error {
+	q := m.query.With(query.WithTable(query.NewTable("generator")))
+	if m.kind == methodInsert {
+		q = q.With(query.WithInsertion(query.NewInsertion([]string{"Id"},
+			query.WithInsertionConflictFields("Id"))))
+	}
+	_, err := translator.Translate(context.Background(), q)
+	return err
+}
+
+func (r *repository) typeName() string {
+	return r.name + TypeSuffix
+}
+
+func (r *repository) write(w *writer) {
+	dialect := dialects[r.dialect]
+	w.line("")
+	w.line("// %s implements %s on the %s table", r.typeName(), r.name, r.table)
+	w.line("type %s struct {", r.typeName())
+	w.line("tm *data.SQLTXManager")
+	w.line("translator query.Translator")
+	for _, m := range r.methods {
+		w.line("%s *query.Query", m.queryField())
+	}
+	w.line("}")
+	w.line("")
+	w.line("var _ %s = (*%s)(nil)", r.name, r.typeName())
+	w.line("")
+	w.line("func New%s(tm *data.SQLTXManager) *%s {", r.typeName(), r.typeName())
+	w.line("parser := data.NewRuleParser()")
+	if len(r.schema) > 0 {
+		w.line("table := query.NewTable(%q, query.WithTableSchema(%q))", r.table, r.schema)
+	} else {
+		w.line("table := query.NewTable(%q)", r.table)
+	}
+	w.line("return &%s{", r.typeName())
+	w.line("tm: tm,")
+	w.line("translator: query.NewRDBTranslator(engine.%s()),", dialect.newFunc)
+	for _, m := range r.methods {
+		w.line("%s: parser.MustParse(%q, query.WithTable(table)),", m.queryField(), m.name)
+	}
+	w.line("}")
+	w.line("}")
+	for _, m := range r.methods {
+		w.line("")
+		r.writeMethod(w, m)
+	}
+	for _, mp := range r.mappers {
+		w.line("")
+		r.writeMapper(w, mp)
+	}
+	for _, scan := range r.scans {
+		w.line("")
+		r.writeScan(w, scan)
+	}
+}
+
+func (r *repository) writeMethod(w *writer, m *method) {
+	params := make([]string, 0, len(m.params))
+	for _, p := range m.params {
+		params = append(params, p.name+" "+p.typ)
+	}
+	results := "err error"
+	if len(m.results) > 1 {
+		results = "result " + m.results[0] + ", err error"
+	}
+	w.line("func (r *%s) %s(%s) (%s) {", r.typeName(), m.name, strings.Join(params, ", "), results)
+
+	executor := fmt.Sprintf("%s, r.tm.OriginTXOrDB(%s), r.translator, q", m.ctx, m.ctx)
+	if m.kind == methodInsert {
+		conflictFields := ""
+		if m.query.Subject() == query.SubjectSave {
+			quoted := make([]string, 0, len(r.keys))
+			for _, key := range r.keys {
+				quoted = append(quoted, strconv.Quote(key))
+			}
+			conflictFields = ", query.WithInsertionConflictFields(" + strings.Join(quoted, ", ") + ")"
+		}
+		w.line("insertion, err := query.NewInsertionFromValues(%s%s)", m.values[0].name, conflictFields)
+		w.line("if err != nil {")
+		w.line("return")
+		w.line("}")
+		w.line("q := r.%s.With(query.WithInsertion(insertion))", m.queryField())
+	} else {
+		values := make([]string, 0, len(m.values))
+		for _, p := range m.values {
+			values = append(values, p.name)
+		}
+		w.line("q, err := r.%s.Bind(%s)", m.queryField(), strings.Join(values, ", "))
+		w.line("if err != nil {")
+		w.line("return")
+		w.line("}")
+	}
+	if m.pager != nil {
+		w.line("if %s != nil {", m.pager.name)
+		w.line("q = q.With(query.WithPager(%s))", m.pager.name)
+		w.line("}")
+	}
+	if m.sortSpec != nil {
+		sortSpec := m.sortSpec.name
+		if m.sortSpec.typ == "[]*query.Sort" {
+			sortSpec = "query.NewSortSpec(" + sortSpec + ")"
+		}
+		allowedFields := ""
+		for _, field := range r.sortFields {
+			allowedFields += ", " + strconv.Quote(field)
+		}
+		w.line("q, err = q.WithSortSpec(%s%s)", sortSpec, allowedFields)
+		w.line("if err != nil {")
+		w.line("return")
+		w.line("}")
+	}
+
+	switch m.kind {
+	case methodFindAll:
+		w.line("return data.FindAll(%s, r.%s)", executor, m.mapper.name)
+	case methodFindOne:
+		w.line("return data.FindOne(%s, r.%s)", executor, m.mapper.name)
+	case methodFindPage:
+		w.line("return data.FindPage(%s, r.%s)", executor, m.mapper.name)
+	case methodCount:
+		w.line("return data.Count(%s)", executor)
+	case methodExists:
+		w.line("return data.Exists(%s)", executor)
+	case methodAggregate:
+		w.line("return data.QueryValue[%s](%s)", m.elemType, executor)
+	case methodFindGroups:
+		w.line("return data.FindGroups(%s, r.%s)", executor, m.mapper.name)
+	default:
+		if len(m.results) > 1 {
+			w.line("return data.Exec(%s)", executor)
+		} else {
+			w.line("_, err = data.Exec(%s)", executor)
+			w.line("return")
+		}
+	}
+	w.line("}")
+}
+
+func (m *method) queryField() string {
+	return strings.ToLower(m.name[:1]) + m.name[1:] + "Query"
+}
+
+// importsOf returns the imports used by the generated source,
+// the imports of the file are kept if the package is referred by the types of the methods
+func importsOf(file *ast.File, repositories []*repository) []string {
+	imports := map[string]string{
+		"context": strconv.Quote("context"),
+		"data":    strconv.Quote("github.com/gomelon/melon/data"),
+		"engine":  strconv.Quote("github.com/gomelon/melon/data/engine"),
+		"query":   strconv.Quote("github.com/gomelon/melon/data/query"),
+	}
+	used := map[string]bool{}
+	for _, repo := range repositories {
+		if len(repo.mappers) > 0 {
+			imports["sql"] = strconv.Quote("database/sql")
+		}
+		if len(repo.scans) > 0 {
+			imports["strings"] = strconv.Quote("strings")
+		}
+		for _, m := range repo.methods {
+			for _, p := range m.params {
+				usedPackagesOf(p.typ, used)
+			}
+			for _, result := range m.results {
+				usedPackagesOf(result, used)
+			}
+		}
+	}
+	for _, importSpec := range file.Imports {
+		path, _ := strconv.Unquote(importSpec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		spec := importSpec.Path.Value
+		if importSpec.Name != nil {
+			name = importSpec.Name.Name
+			spec = name + " " + spec
+		}
+		if used[name] {
+			if _, ok := imports[name]; !ok {
+				imports[name] = spec
+			}
+		}
+	}
+	specs := make([]string, 0, len(imports))
+	for _, spec := range imports {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return strings.Trim(specs[i][strings.Index(specs[i], `"`):], `"`) <
+			strings.Trim(specs[j][strings.Index(specs[j], `"`):], `"`)
+	})
+	return specs
+}
+
+// usedPackagesOf collects the package names referred by the type expression, EX: []*model.User refers model
+func usedPackagesOf(typ string, used map[string]bool) {
+	expr, err := parser.ParseExpr(typ)
+	if err != nil {
+		return
+	}
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+}
+
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	_ = printer.Fprint(&buf, fset, expr)
+	return buf.String()
+}
+
+type writer struct {
+	bytes.Buffer
+}
+
+func (w *writer) line(format string, args ...any) {
+	_, _ = fmt.Fprintf(w, format, args...)
+	w.WriteByte('\n')
+}
@@ -0,0 +1,213 @@
+package generator
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerator_GenerateFile(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filepath.Join("testdata", "repo", "user.go"), nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewGenerator().GenerateFile(fset, file)
+	if err != nil {
+		t.Errorf("GenerateFile() error = %v", err)
+		return
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "repo", "user_melon.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("GenerateFile() \nactual = %s, \nexpect = %s", got, want)
+	}
+}
+
+func TestGenerator_GenerateFile_Mapper(t *testing.T) {
+	fset := token.NewFileSet()
+	model, err := parser.ParseFile(fset, "model.go", `package repo
+type Base struct {
+	Id        int64
+	CreatedAt time.Time
+}
+type User struct {
+	Base
+	Id       int64  `+"`db:\"user_id\"`"+`
+	UserName string
+	Password string `+"`db:\"-\"`"+`
+	secret   string
+}`, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := parser.ParseFile(fset, "user.go", `package repo
+//melon:repository table=user dialect=oracle
+type UserRepository interface {
+	FindById(ctx context.Context, id int64) (*User, error)
+}`, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewGenerator().GenerateFile(fset, file, model)
+	if err != nil {
+		t.Errorf("GenerateFile() error = %v", err)
+		return
+	}
+	want := `		switch strings.ToLower(column) {
+		case "user_id":
+			dest = append(dest, &value.Id)
+		case "user_name":
+			dest = append(dest, &value.UserName)
+		case "id":
+			dest = append(dest, &value.Base.Id)
+		case "created_at":
+			dest = append(dest, &value.Base.CreatedAt)
+		default:
+			dest = append(dest, new(any))
+		}`
+	if !strings.Contains(string(got), want) {
+		t.Errorf("GenerateFile() \nactual = %s, \nexpect contains = %s", got, want)
+	}
+}
+
+func TestGenerator_GenerateFile_Error(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       string
+		wantNil   bool
+		wantError string
+	}{
+		{
+			name: "not annotated",
+			src: `package repo
+type UserRepository interface {
+	FindById(ctx context.Context, id int64) (*User, error)
+}`,
+			wantNil: true,
+		},
+		{
+			name: "missing table",
+			src: `package repo
+//melon:repository dialect=mysql
+type UserRepository interface {
+	FindById(ctx context.Context, id int64) (*User, error)
+}`,
+			wantError: "must has the table option",
+		},
+		{
+			name: "unsupported dialect",
+			src: `package repo
+//melon:repository table=user dialect=mongo
+type UserRepository interface {
+	FindById(ctx context.Context, id int64) (*User, error)
+}`,
+			wantError: "unsupported dialect [mongo]",
+		},
+		{
+			name: "unparsable method",
+			src: `package repo
+//melon:repository table=user
+type UserRepository interface {
+	LoadById(ctx context.Context, id int64) (*User, error)
+}`,
+			wantError: "can not find subject",
+		},
+		{
+			name: "missing context",
+			src: `package repo
+//melon:repository table=user
+type UserRepository interface {
+	FindById(id int64) (*User, error)
+}`,
+			wantError: "the first parameter must be context.Context",
+		},
+		{
+			name: "value parameters mismatch filters",
+			src: `package repo
+//melon:repository table=user
+type UserRepository interface {
+	FindByIdAndName(ctx context.Context, id int64) (*User, error)
+}`,
+			wantError: "expected 2 value parameters, but actual 1 value parameters",
+		},
+		{
+			name: "count result",
+			src: `package repo
+//melon:repository table=user
+type UserRepository interface {
+	CountByStatus(ctx context.Context, status int) (int, error)
+}`,
+			wantError: "count must returns int64 and error",
+		},
+		{
+			name: "grouped count result",
+			src: `package repo
+//melon:repository table=user
+type UserRepository interface {
+	CountGroupByStatus(ctx context.Context) (int64, error)
+}`,
+			wantError: "grouped count must returns the slice of the groups and error",
+		},
+		{
+			name: "page without pager",
+			src: `package repo
+//melon:repository table=user
+type UserRepository interface {
+	FindByStatus(ctx context.Context, status int) (*query.Page[User], error)
+}`,
+			wantError: "find page must has the pager parameter",
+		},
+		{
+			name: "untranslatable on dialect",
+			src: `package repo
+//melon:repository table=user dialect=sqlserver
+type UserRepository interface {
+	FindByNameMatches(ctx context.Context, name string) ([]*User, error)
+}`,
+			wantError: "translate query fail",
+		},
+		{
+			name: "result type not found",
+			src: `package repo
+//melon:repository table=user
+type UserRepository interface {
+	FindById(ctx context.Context, id int64) (*Account, error)
+}`,
+			wantError: "can not find the type Account in the package",
+		},
+		{
+			name: "reserved parameter name",
+			src: `package repo
+//melon:repository table=user
+type UserRepository interface {
+	FindById(ctx context.Context, q int64) (*User, error)
+}`,
+			wantError: "parameter name [q] is reserved",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "user.go", tt.src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := NewGenerator().GenerateFile(fset, file)
+			if tt.wantNil {
+				if got != nil || err != nil {
+					t.Errorf("GenerateFile() = %s, error = %v, want nil", got, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("GenerateFile() error = %v, want %s", err, tt.wantError)
+			}
+		})
+	}
+}
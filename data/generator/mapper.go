@@ -0,0 +1,230 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// mapper is the generated RowMapper of the type, the struct or pointer to struct is scanned by its structScan,
+// the other types are scanned from the only column
+type mapper struct {
+	name string
+	typ  string
+	scan *structScan
+}
+
+// structScan is the generated method scans the columns of the row to the fields of the struct
+type structScan struct {
+	name       string
+	structName string
+	fields     []*scanField
+}
+
+// scanField is the field scanned from the column, the path selects the field from the struct, EX: Base.Id
+type scanField struct {
+	column string
+	path   string
+}
+
+// typesOf returns the types declared in the files by their names
+func typesOf(files []*ast.File) map[string]ast.Expr {
+	typeDecls := map[string]ast.Expr{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				typeDecls[typeSpec.Name.Name] = typeSpec.Type
+			}
+		}
+	}
+	return typeDecls
+}
+
+// parseMappers the methods mapping the rows share the mapper of the same type
+func (r *repository) parseMappers(typeDecls map[string]ast.Expr) error {
+	for _, m := range r.methods {
+		if m.kind != methodFindAll && m.kind != methodFindOne && m.kind != methodFindPage && m.kind != methodFindGroups {
+			continue
+		}
+		var err error
+		if m.mapper, err = r.mapperOf(m.elemType, typeDecls); err != nil {
+			return fmt.Errorf("method %s.%s: %w", r.name, m.name, err)
+		}
+	}
+	return nil
+}
+
+// mapperOf returns the mapper of the type. The struct or pointer to struct declared in the package is mapped
+// column by column, the column of the field is the db tag, or the column built by Engine.BuildColumn
+// from the field name, and matched ignoring case. The fields of the embedded structs are promoted,
+// the columns without field are discarded. The other types, include the types of other packages,
+// are scanned from the only column
+func (r *repository) mapperOf(typ string, typeDecls map[string]ast.Expr) (*mapper, error) {
+	for _, mp := range r.mappers {
+		if mp.typ == typ {
+			return mp, nil
+		}
+	}
+	mp := &mapper{typ: typ}
+	typeName := strings.TrimPrefix(typ, "*")
+	if typeExpr, ok := typeDecls[typeName]; ok {
+		if structType, ok := typeExpr.(*ast.StructType); ok {
+			mp.scan = r.scanOf(typeName, structType, typeDecls)
+		}
+	} else if token.IsIdentifier(typeName) && types.Universe.Lookup(typeName) == nil {
+		return nil, fmt.Errorf("can not find the type %s in the package", typeName)
+	}
+	mp.name = mapperName(typ)
+	for i := 2; r.hasMapper(mp.name); i++ {
+		mp.name = mapperName(typ) + strconv.Itoa(i)
+	}
+	r.mappers = append(r.mappers, mp)
+	return mp, nil
+}
+
+func (r *repository) hasMapper(name string) bool {
+	for _, mp := range r.mappers {
+		if mp.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mapperName EX: *User is mapUserPtr, []byte is mapByteSlice, sql.NullString is mapSqlNullString
+func mapperName(typ string) string {
+	name := "map"
+	for _, part := range strings.FieldsFunc(typ, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		name += strings.ToUpper(part[:1]) + part[1:]
+	}
+	if strings.HasPrefix(typ, "*") {
+		name += "Ptr"
+	} else if strings.HasPrefix(typ, "[]") {
+		name += "Slice"
+	}
+	return name
+}
+
+func (r *repository) scanOf(structName string, structType *ast.StructType, typeDecls map[string]ast.Expr,
+) *structScan {
+	for _, scan := range r.scans {
+		if scan.structName == structName {
+			return scan
+		}
+	}
+	scan := &structScan{name: "scan" + strings.ToUpper(structName[:1]) + structName[1:], structName: structName}
+	r.scanFields(scan, structType, "", typeDecls, map[string]bool{})
+	r.scans = append(r.scans, scan)
+	return scan
+}
+
+// scanFields the fields of the outer struct shadow the fields of the embedded structs with the same column,
+// the embedded pointers are not promoted
+func (r *repository) scanFields(scan *structScan, structType *ast.StructType, parentPath string,
+	typeDecls map[string]ast.Expr, columns map[string]bool) {
+
+	engin := dialects[r.dialect].engin
+	var embedded []*ast.StructType
+	var embeddedPaths []string
+	for _, field := range structType.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			tagValue, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(tagValue).Get("db")
+		}
+		if tag == "-" {
+			continue
+		}
+		names := field.Names
+		if len(names) == 0 {
+			typeExpr := field.Type
+			if starExpr, ok := typeExpr.(*ast.StarExpr); ok {
+				typeExpr = starExpr.X
+			}
+			var ident *ast.Ident
+			switch expr := typeExpr.(type) {
+			case *ast.Ident:
+				ident = expr
+			case *ast.SelectorExpr:
+				ident = expr.Sel
+			default:
+				continue
+			}
+			embeddedType, ok := typeDecls[ident.Name].(*ast.StructType)
+			if ok && typeExpr == ident && len(tag) == 0 {
+				if field.Type == ident {
+					embedded = append(embedded, embeddedType)
+					embeddedPaths = append(embeddedPaths, parentPath+ident.Name+".")
+				}
+				continue
+			}
+			names = []*ast.Ident{ident}
+		}
+		for _, name := range names {
+			if !name.IsExported() {
+				continue
+			}
+			column := tag
+			if len(column) == 0 {
+				column = engin.BuildColumn(name.Name)
+			}
+			column = strings.ToLower(column)
+			if columns[column] {
+				continue
+			}
+			columns[column] = true
+			scan.fields = append(scan.fields, &scanField{column: column, path: parentPath + name.Name})
+		}
+	}
+	for i, embeddedType := range embedded {
+		r.scanFields(scan, embeddedType, embeddedPaths[i], typeDecls, columns)
+	}
+}
+
+func (r *repository) writeMapper(w *writer, mp *mapper) {
+	w.line("func (r *%s) %s(rows *sql.Rows) (value %s, err error) {", r.typeName(), mp.name, mp.typ)
+	switch {
+	case mp.scan == nil:
+		w.line("err = rows.Scan(&value)")
+	case strings.HasPrefix(mp.typ, "*"):
+		w.line("value = &%s{}", mp.scan.structName)
+		w.line("err = r.%s(rows, value)", mp.scan.name)
+	default:
+		w.line("err = r.%s(rows, &value)", mp.scan.name)
+	}
+	w.line("return")
+	w.line("}")
+}
+
+func (r *repository) writeScan(w *writer, scan *structScan) {
+	w.line("func (r *%s) %s(rows *sql.Rows, value *%s) error {", r.typeName(), scan.name, scan.structName)
+	w.line("columns, err := rows.Columns()")
+	w.line("if err != nil {")
+	w.line("return err")
+	w.line("}")
+	w.line("dest := make([]any, 0, len(columns))")
+	w.line("for _, column := range columns {")
+	w.line("switch strings.ToLower(column) {")
+	for _, field := range scan.fields {
+		w.line("case %q:", field.column)
+		w.line("dest = append(dest, &value.%s)", field.path)
+	}
+	w.line("default:")
+	w.line("dest = append(dest, new(any))")
+	w.line("}")
+	w.line("}")
+	w.line("return rows.Scan(dest...)")
+	w.line("}")
+}
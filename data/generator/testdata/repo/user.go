@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gomelon/melon/data/query"
+)
+
+type User struct {
+	Id        int64
+	Name      string
+	Email     string
+	Status    int
+	Balance   float64
+	CreatedAt time.Time
+}
+
+type StatusCount struct {
+	Status int
+	X      int64
+}
+
+//melon:repository table=user dialect=mysql sorts=Name,CreatedAt
+type UserRepository interface {
+	FindById(ctx context.Context, id int64) (*User, error)
+	FindByStatusOrderByIdDesc(ctx context.Context, status int, pager query.Pager, sortSpec *query.SortSpec) ([]*User, error)
+	FindNameByIdIn(ctx context.Context, ids []int64) ([]string, error)
+	FindByNameContains(ctx context.Context, name string, pager *query.PageRequest) (*query.Page[User], error)
+	CountByStatus(ctx context.Context, status int) (int64, error)
+	CountGroupByStatus(ctx context.Context) ([]*StatusCount, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	SumBalanceByStatus(ctx context.Context, status int) (sql.NullFloat64, error)
+	UpdateStatusById(ctx context.Context, status int, id int64) (int64, error)
+	DeleteById(ctx context.Context, id int64) error
+	Insert(ctx context.Context, users []*User) (int64, error)
+	Save(ctx context.Context, user *User) (int64, error)
+}
+
+type NotRepository interface {
+	Anything(ctx context.Context) error
+}
@@ -0,0 +1,230 @@
+// Code generated by melon gen. DO NOT EDIT.
+
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"github.com/gomelon/melon/data"
+	"github.com/gomelon/melon/data/engine"
+	"github.com/gomelon/melon/data/query"
+	"strings"
+)
+
+// UserRepositoryMelon implements UserRepository on the user table
+type UserRepositoryMelon struct {
+	tm                             *data.SQLTXManager
+	translator                     query.Translator
+	findByIdQuery                  *query.Query
+	findByStatusOrderByIdDescQuery *query.Query
+	findNameByIdInQuery            *query.Query
+	findByNameContainsQuery        *query.Query
+	countByStatusQuery             *query.Query
+	countGroupByStatusQuery        *query.Query
+	existsByEmailQuery             *query.Query
+	sumBalanceByStatusQuery        *query.Query
+	updateStatusByIdQuery          *query.Query
+	deleteByIdQuery                *query.Query
+	insertQuery                    *query.Query
+	saveQuery                      *query.Query
+}
+
+var _ UserRepository = (*UserRepositoryMelon)(nil)
+
+func NewUserRepositoryMelon(tm *data.SQLTXManager) *UserRepositoryMelon {
+	parser := data.NewRuleParser()
+	table := query.NewTable("user")
+	return &UserRepositoryMelon{
+		tm:                             tm,
+		translator:                     query.NewRDBTranslator(engine.NewMySQL()),
+		findByIdQuery:                  parser.MustParse("FindById", query.WithTable(table)),
+		findByStatusOrderByIdDescQuery: parser.MustParse("FindByStatusOrderByIdDesc", query.WithTable(table)),
+		findNameByIdInQuery:            parser.MustParse("FindNameByIdIn", query.WithTable(table)),
+		findByNameContainsQuery:        parser.MustParse("FindByNameContains", query.WithTable(table)),
+		countByStatusQuery:             parser.MustParse("CountByStatus", query.WithTable(table)),
+		countGroupByStatusQuery:        parser.MustParse("CountGroupByStatus", query.WithTable(table)),
+		existsByEmailQuery:             parser.MustParse("ExistsByEmail", query.WithTable(table)),
+		sumBalanceByStatusQuery:        parser.MustParse("SumBalanceByStatus", query.WithTable(table)),
+		updateStatusByIdQuery:          parser.MustParse("UpdateStatusById", query.WithTable(table)),
+		deleteByIdQuery:                parser.MustParse("DeleteById", query.WithTable(table)),
+		insertQuery:                    parser.MustParse("Insert", query.WithTable(table)),
+		saveQuery:                      parser.MustParse("Save", query.WithTable(table)),
+	}
+}
+
+func (r *UserRepositoryMelon) FindById(ctx context.Context, id int64) (result *User, err error) {
+	q, err := r.findByIdQuery.Bind(id)
+	if err != nil {
+		return
+	}
+	return data.FindOne(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q, r.mapUserPtr)
+}
+
+func (r *UserRepositoryMelon) FindByStatusOrderByIdDesc(ctx context.Context, status int, pager query.Pager, sortSpec *query.SortSpec) (result []*User, err error) {
+	q, err := r.findByStatusOrderByIdDescQuery.Bind(status)
+	if err != nil {
+		return
+	}
+	if pager != nil {
+		q = q.With(query.WithPager(pager))
+	}
+	q, err = q.WithSortSpec(sortSpec, "Name", "CreatedAt")
+	if err != nil {
+		return
+	}
+	return data.FindAll(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q, r.mapUserPtr)
+}
+
+func (r *UserRepositoryMelon) FindNameByIdIn(ctx context.Context, ids []int64) (result []string, err error) {
+	q, err := r.findNameByIdInQuery.Bind(ids)
+	if err != nil {
+		return
+	}
+	return data.FindAll(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q, r.mapString)
+}
+
+func (r *UserRepositoryMelon) FindByNameContains(ctx context.Context, name string, pager *query.PageRequest) (result *query.Page[User], err error) {
+	q, err := r.findByNameContainsQuery.Bind(name)
+	if err != nil {
+		return
+	}
+	if pager != nil {
+		q = q.With(query.WithPager(pager))
+	}
+	return data.FindPage(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q, r.mapUser)
+}
+
+func (r *UserRepositoryMelon) CountByStatus(ctx context.Context, status int) (result int64, err error) {
+	q, err := r.countByStatusQuery.Bind(status)
+	if err != nil {
+		return
+	}
+	return data.Count(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q)
+}
+
+func (r *UserRepositoryMelon) CountGroupByStatus(ctx context.Context) (result []*StatusCount, err error) {
+	q, err := r.countGroupByStatusQuery.Bind()
+	if err != nil {
+		return
+	}
+	return data.FindGroups(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q, r.mapStatusCountPtr)
+}
+
+func (r *UserRepositoryMelon) ExistsByEmail(ctx context.Context, email string) (result bool, err error) {
+	q, err := r.existsByEmailQuery.Bind(email)
+	if err != nil {
+		return
+	}
+	return data.Exists(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q)
+}
+
+func (r *UserRepositoryMelon) SumBalanceByStatus(ctx context.Context, status int) (result sql.NullFloat64, err error) {
+	q, err := r.sumBalanceByStatusQuery.Bind(status)
+	if err != nil {
+		return
+	}
+	return data.QueryValue[sql.NullFloat64](ctx, r.tm.OriginTXOrDB(ctx), r.translator, q)
+}
+
+func (r *UserRepositoryMelon) UpdateStatusById(ctx context.Context, status int, id int64) (result int64, err error) {
+	q, err := r.updateStatusByIdQuery.Bind(status, id)
+	if err != nil {
+		return
+	}
+	return data.Exec(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q)
+}
+
+func (r *UserRepositoryMelon) DeleteById(ctx context.Context, id int64) (err error) {
+	q, err := r.deleteByIdQuery.Bind(id)
+	if err != nil {
+		return
+	}
+	_, err = data.Exec(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q)
+	return
+}
+
+func (r *UserRepositoryMelon) Insert(ctx context.Context, users []*User) (result int64, err error) {
+	insertion, err := query.NewInsertionFromValues(users)
+	if err != nil {
+		return
+	}
+	q := r.insertQuery.With(query.WithInsertion(insertion))
+	return data.Exec(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q)
+}
+
+func (r *UserRepositoryMelon) Save(ctx context.Context, user *User) (result int64, err error) {
+	insertion, err := query.NewInsertionFromValues(user, query.WithInsertionConflictFields("Id"))
+	if err != nil {
+		return
+	}
+	q := r.saveQuery.With(query.WithInsertion(insertion))
+	return data.Exec(ctx, r.tm.OriginTXOrDB(ctx), r.translator, q)
+}
+
+func (r *UserRepositoryMelon) mapUserPtr(rows *sql.Rows) (value *User, err error) {
+	value = &User{}
+	err = r.scanUser(rows, value)
+	return
+}
+
+func (r *UserRepositoryMelon) mapString(rows *sql.Rows) (value string, err error) {
+	err = rows.Scan(&value)
+	return
+}
+
+func (r *UserRepositoryMelon) mapUser(rows *sql.Rows) (value User, err error) {
+	err = r.scanUser(rows, &value)
+	return
+}
+
+func (r *UserRepositoryMelon) mapStatusCountPtr(rows *sql.Rows) (value *StatusCount, err error) {
+	value = &StatusCount{}
+	err = r.scanStatusCount(rows, value)
+	return
+}
+
+func (r *UserRepositoryMelon) scanUser(rows *sql.Rows, value *User) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	dest := make([]any, 0, len(columns))
+	for _, column := range columns {
+		switch strings.ToLower(column) {
+		case "id":
+			dest = append(dest, &value.Id)
+		case "name":
+			dest = append(dest, &value.Name)
+		case "email":
+			dest = append(dest, &value.Email)
+		case "status":
+			dest = append(dest, &value.Status)
+		case "balance":
+			dest = append(dest, &value.Balance)
+		case "created_at":
+			dest = append(dest, &value.CreatedAt)
+		default:
+			dest = append(dest, new(any))
+		}
+	}
+	return rows.Scan(dest...)
+}
+
+func (r *UserRepositoryMelon) scanStatusCount(rows *sql.Rows, value *StatusCount) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	dest := make([]any, 0, len(columns))
+	for _, column := range columns {
+		switch strings.ToLower(column) {
+		case "status":
+			dest = append(dest, &value.Status)
+		case "x":
+			dest = append(dest, &value.X)
+		default:
+			dest = append(dest, new(any))
+		}
+	}
+	return rows.Scan(dest...)
+}
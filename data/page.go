@@ -0,0 +1,138 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gomelon/melon/data/query"
+)
+
+// RowMapper maps the current row of the rows to the value
+type RowMapper[T any] func(rows *sql.Rows) (T, error)
+
+// FindPage runs the Find query with its pager, and runs the Count query of the same query if the pager searches count,
+// the count is skipped if the page is short, the total is inferred from the offset and the content instead
+func FindPage[T any](ctx context.Context, executor SQLExecutor, translator query.Translator, q *query.Query,
+	mapper RowMapper[T]) (page *query.Page[T], err error) {
+
+	pager := q.Pager()
+	content, err := FindAll(ctx, executor, translator, q, mapper)
+	if err != nil {
+		return
+	}
+	if pager == nil || !pager.SearchCount() {
+		page = query.NewPageWithoutCount(content, pager)
+		return
+	}
+	if len(content) < pager.PageSize() && (len(content) > 0 || pager.Offset() == 0) {
+		page = query.NewPage(content, pager, int64(pager.Offset()+len(content)))
+		return
+	}
+	total, err := Count(ctx, executor, translator, q)
+	if err != nil {
+		return
+	}
+	page = query.NewPage(content, pager, total)
+	return
+}
+
+// FindAll runs the Find query, each row is mapped by the mapper
+func FindAll[T any](ctx context.Context, executor SQLExecutor, translator query.Translator, q *query.Query,
+	mapper RowMapper[T]) (result []T, err error) {
+
+	statement, err := translator.TranslateFind(ctx, q)
+	if err != nil {
+		return
+	}
+	if len(statement.NamedArgs()) > 0 {
+		err = fmt.Errorf("find fail: named args %v must be bound before executing", statement.NamedArgs())
+		return
+	}
+	return queryAll(ctx, executor, statement, mapper)
+}
+
+// FindGroups runs the grouped Count or aggregate query, each row of the grouping columns followed by
+// the aggregate value X is mapped by the mapper, EX: CountGroupByStatus
+func FindGroups[T any](ctx context.Context, executor SQLExecutor, translator query.Translator, q *query.Query,
+	mapper RowMapper[T]) (result []T, err error) {
+
+	if len(q.GroupBy()) == 0 {
+		err = fmt.Errorf("find groups fail: the query is not grouped")
+		return
+	}
+	statement, err := translator.Translate(ctx, q)
+	if err != nil {
+		return
+	}
+	if len(statement.NamedArgs()) > 0 {
+		err = fmt.Errorf("find groups fail: named args %v must be bound before executing", statement.NamedArgs())
+		return
+	}
+	return queryAll(ctx, executor, statement, mapper)
+}
+
+func queryAll[T any](ctx context.Context, executor SQLExecutor, statement *query.Statement,
+	mapper RowMapper[T]) (result []T, err error) {
+
+	rows, err := executor.QueryContext(ctx, statement.SQL(), statement.Args()...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var value T
+		value, err = mapper(rows)
+		if err != nil {
+			return
+		}
+		result = append(result, value)
+	}
+	err = rows.Err()
+	return
+}
+
+// Count runs the Count query of the same filters, the sorts and pager of the query are ignored,
+// the projection is ignored too unless the query is distinct, the groups are counted if the query is grouped
+func Count(ctx context.Context, executor SQLExecutor, translator query.Translator, q *query.Query,
+) (total int64, err error) {
+
+	if len(q.GroupBy()) > 0 {
+		return countBySubquery(ctx, executor, translator, q.With(
+			query.WithSubject(query.SubjectFind),
+			query.WithSorts(nil),
+			query.WithPager(nil),
+		))
+	}
+	opts := []query.Option{query.WithSubject(query.SubjectCount), query.WithSorts(nil), query.WithPager(nil)}
+	if q.SubjectModifier() != query.SubjectModifierDistinct {
+		opts = append(opts, query.WithSubjectModifier(nil), query.WithProjection(nil))
+	}
+	statement, err := translator.TranslateCount(ctx, q.With(opts...))
+	if err != nil {
+		return
+	}
+	if len(statement.NamedArgs()) > 0 {
+		err = fmt.Errorf("count fail: named args %v must be bound before executing", statement.NamedArgs())
+		return
+	}
+	err = executor.QueryRowContext(ctx, statement.SQL(), statement.Args()...).Scan(&total)
+	return
+}
+
+// countBySubquery counts the rows of the Find query, it is used when the rows can not be counted by COUNT(*)
+// of the same filters, EX: the groups
+func countBySubquery(ctx context.Context, executor SQLExecutor, translator query.Translator, q *query.Query,
+) (total int64, err error) {
+
+	statement, err := translator.TranslateFind(ctx, q)
+	if err != nil {
+		return
+	}
+	if len(statement.NamedArgs()) > 0 {
+		err = fmt.Errorf("count fail: named args %v must be bound before executing", statement.NamedArgs())
+		return
+	}
+	countSQL := "SELECT COUNT(*) FROM (" + statement.SQL() + ") X"
+	err = executor.QueryRowContext(ctx, countSQL, statement.Args()...).Scan(&total)
+	return
+}
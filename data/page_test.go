@@ -0,0 +1,167 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/gomelon/melon/data/engine"
+	"github.com/gomelon/melon/data/query"
+	"reflect"
+	"testing"
+)
+
+func TestFindPage(t *testing.T) {
+	findSQL := "SELECT `id` FROM `user` WHERE (`status` = ?) ORDER BY `id` ASC LIMIT ?, ?"
+	countSQL := "SELECT COUNT(*) AS X FROM `user` WHERE (`status` = ?)"
+	newQuery := func(pager query.Pager) *query.Query {
+		return query.New(
+			query.SubjectFind,
+			query.WithTable(query.NewTable("user")),
+			query.WithProjection(query.NewProjection("Id")),
+			query.WithFilterGroup(query.NewFilterGroupWithFilters([]*query.Filter{
+				query.NewFilter("Status", query.PredicateIs, query.WithFilterValues(1)),
+			}, query.LogicOperatorAnd)),
+			query.WithSorts([]*query.Sort{query.NewSort("Id", query.DirectionAsc)}),
+			query.WithPager(pager),
+		)
+	}
+	tests := []struct {
+		name           string
+		query          *query.Query
+		rows           [][]driver.Value
+		total          int64
+		wantContent    []int64
+		wantTotal      int64
+		wantTotalPages int
+		wantHasNext    bool
+		wantHasPrev    bool
+		wantExecuted   []string
+	}{
+		{
+			name:           "full page counted",
+			query:          newQuery(query.NewPageRequest(2, 2, true)),
+			rows:           [][]driver.Value{{int64(3)}, {int64(4)}},
+			total:          5,
+			wantContent:    []int64{3, 4},
+			wantTotal:      5,
+			wantTotalPages: 3,
+			wantHasNext:    true,
+			wantHasPrev:    true,
+			wantExecuted:   []string{findSQL, countSQL},
+		},
+		{
+			name:           "short first page skips count",
+			query:          newQuery(query.NewPageRequest(1, 2, true)),
+			rows:           [][]driver.Value{{int64(1)}},
+			wantContent:    []int64{1},
+			wantTotal:      1,
+			wantTotalPages: 1,
+			wantHasNext:    false,
+			wantHasPrev:    false,
+			wantExecuted:   []string{findSQL},
+		},
+		{
+			name:           "short last page skips count",
+			query:          newQuery(query.NewPageRequest(3, 2, true)),
+			rows:           [][]driver.Value{{int64(5)}},
+			wantContent:    []int64{5},
+			wantTotal:      5,
+			wantTotalPages: 3,
+			wantHasNext:    false,
+			wantHasPrev:    true,
+			wantExecuted:   []string{findSQL},
+		},
+		{
+			name:           "empty page after last counted",
+			query:          newQuery(query.NewPageRequest(4, 2, true)),
+			total:          5,
+			wantTotal:      5,
+			wantTotalPages: 3,
+			wantHasNext:    false,
+			wantHasPrev:    true,
+			wantExecuted:   []string{findSQL, countSQL},
+		},
+		{
+			name:           "without count",
+			query:          newQuery(query.NewPageRequest(1, 2, false)),
+			rows:           [][]driver.Value{{int64(1)}, {int64(2)}},
+			wantContent:    []int64{1, 2},
+			wantTotal:      -1,
+			wantTotalPages: -1,
+			wantHasNext:    true,
+			wantHasPrev:    false,
+			wantExecuted:   []string{findSQL},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeDB()
+			fake.returns(findSQL, []string{"id"}, tt.rows...)
+			fake.returns(countSQL, []string{"X"}, []driver.Value{tt.total})
+			db := fake.open()
+			defer db.Close()
+
+			translator := query.NewRDBTranslator(engine.NewMySQL())
+			page, err := FindPage(context.Background(), db, translator, tt.query, func(rows *sql.Rows) (id int64, err error) {
+				err = rows.Scan(&id)
+				return
+			})
+			if err != nil {
+				t.Errorf("FindPage() error = %v", err)
+				return
+			}
+			if len(page.Content()) > 0 || len(tt.wantContent) > 0 {
+				if !reflect.DeepEqual(page.Content(), tt.wantContent) {
+					t.Errorf("FindPage() content = %v, want %v", page.Content(), tt.wantContent)
+				}
+			}
+			if page.Total() != tt.wantTotal || page.TotalPages() != tt.wantTotalPages {
+				t.Errorf("FindPage() total = %d/%d pages, want %d/%d pages",
+					page.Total(), page.TotalPages(), tt.wantTotal, tt.wantTotalPages)
+			}
+			if page.HasNext() != tt.wantHasNext || page.HasPrevious() != tt.wantHasPrev {
+				t.Errorf("FindPage() hasNext = %v, hasPrevious = %v, want %v, %v",
+					page.HasNext(), page.HasPrevious(), tt.wantHasNext, tt.wantHasPrev)
+			}
+			if executed := fake.log(); !reflect.DeepEqual(executed, tt.wantExecuted) {
+				t.Errorf("FindPage() executed = %v, want %v", executed, tt.wantExecuted)
+			}
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	fake := newFakeDB()
+	fake.returns("SELECT COUNT(*) AS X FROM `user` WHERE (`status` = ?)", []string{"X"}, []driver.Value{int64(3)})
+	fake.returns("SELECT COUNT(*) AS X FROM (SELECT DISTINCT `name` FROM `user` WHERE (`status` = ?)) T",
+		[]string{"X"}, []driver.Value{int64(2)})
+	fake.returns("SELECT COUNT(*) AS X FROM (SELECT DISTINCT * FROM `user` WHERE (`status` = ?)) T",
+		[]string{"X"}, []driver.Value{int64(1)})
+	fake.returns("SELECT COUNT(*) FROM (SELECT `name` FROM `user` WHERE (`status` = ?) GROUP BY `name`) X",
+		[]string{"COUNT(*)"}, []driver.Value{int64(4)})
+	db := fake.open()
+	defer db.Close()
+
+	ctx := context.Background()
+	translator := query.NewRDBTranslator(engine.NewMySQL())
+	parser := NewRuleParser()
+	table := query.WithTable(query.NewTable("user"))
+	tests := []struct {
+		method    string
+		wantTotal int64
+	}{
+		{method: "FindNameByStatus", wantTotal: 3},
+		{method: "FindDistinctNameByStatus", wantTotal: 2},
+		{method: "CountDistinctByStatus", wantTotal: 1},
+		{method: "FindByStatusGroupByName", wantTotal: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			q, _ := parser.MustParse(tt.method, table).Bind("A")
+			total, err := Count(ctx, db, translator, q)
+			if err != nil || total != tt.wantTotal {
+				t.Errorf("Count() = %v, error = %v, want %v", total, err, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Assignment is an element of the set clause of the Update subject,
+// the value is nil if it has not been filled
+type Assignment struct {
+	fieldName string
+	value     any
+	namedArg  string
+}
+
+func NewAssignment(fieldName string, opts ...AssignmentOption) *Assignment {
+	assignment := &Assignment{fieldName: fieldName}
+	for _, opt := range opts {
+		opt(assignment)
+	}
+	return assignment
+}
+
+// NewAssignments returns the assignments of the fields, the values are filled later
+func NewAssignments(fieldNames ...string) []*Assignment {
+	assignments := make([]*Assignment, 0, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		assignments = append(assignments, NewAssignment(fieldName))
+	}
+	return assignments
+}
+
+// FillAssignmentValues fill the values of the assignments in order
+func FillAssignmentValues(assignments []*Assignment, values []any) error {
+	if len(assignments) != len(values) {
+		return fmt.Errorf("expected %d values, but actual %d values", len(assignments), len(values))
+	}
+	for i, assignment := range assignments {
+		assignment.value = values[i]
+	}
+	return nil
+}
+
+func (a *Assignment) FieldName() string {
+	return a.fieldName
+}
+
+func (a *Assignment) Value() any {
+	return a.value
+}
+
+func (a *Assignment) NamedArg() string {
+	return a.namedArg
+}
+
+func (a Assignment) String() string {
+	builder := strings.Builder{}
+	builder.Grow(64)
+	builder.WriteString(a.fieldName)
+	builder.WriteString(" = ")
+	if len(a.namedArg) > 0 {
+		builder.WriteRune(':')
+		builder.WriteString(a.namedArg)
+	} else {
+		builder.WriteString(fmt.Sprintf("%#v", a.value))
+	}
+	return builder.String()
+}
+
+type AssignmentOption func(assignment *Assignment)
+
+func WithAssignmentValue(value any) AssignmentOption {
+	return func(assignment *Assignment) {
+		assignment.value = value
+	}
+}
+
+func WithAssignmentNamedArg(namedArg string) AssignmentOption {
+	return func(assignment *Assignment) {
+		assignment.namedArg = namedArg
+	}
+}
@@ -0,0 +1,33 @@
+package query
+
+import "strings"
+
+// FieldPath is the path of the nested field, the segments are separated by _ in the field name,
+// EX: the path of Address_City is [Address City].
+// The fields of the projection are not nested, they may be the columns of the db tags, EX: created_at
+type FieldPath []string
+
+func NewFieldPath(fieldName string) FieldPath {
+	return strings.Split(fieldName, "_")
+}
+
+func (p FieldPath) IsNested() bool {
+	return len(p) > 1
+}
+
+func (p FieldPath) Root() string {
+	return p[0]
+}
+
+func (p FieldPath) Leaf() string {
+	return p[len(p)-1]
+}
+
+// Parent the path without the leaf, it is empty if the path is not nested
+func (p FieldPath) Parent() FieldPath {
+	return p[:len(p)-1]
+}
+
+func (p FieldPath) String() string {
+	return strings.Join(p, "_")
+}
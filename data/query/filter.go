@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -19,6 +20,18 @@ func NewFilterGroupWithFilters(filters []*Filter, logicOperator LogicOperator) *
 	return &FilterGroup{filters: filters, logicOperator: logicOperator}
 }
 
+func (fg *FilterGroup) Groups() []*FilterGroup {
+	return fg.groups
+}
+
+func (fg *FilterGroup) Filters() []*Filter {
+	return fg.filters
+}
+
+func (fg *FilterGroup) LogicOperator() LogicOperator {
+	return fg.logicOperator
+}
+
 func (fg *FilterGroup) NumValue() (num int) {
 	for _, group := range fg.groups {
 		num += group.NumValue()
@@ -49,10 +62,67 @@ func (fg *FilterGroup) FillNamedArgs(namedArgs []string) error {
 	return nil
 }
 
+// PropagateIgnoreCase if any filter is modified by AllIgnoreCase,
+// every string comparable filter without modifier in the group and the nested groups is modified by AllIgnoreCase
+func (fg *FilterGroup) PropagateIgnoreCase() {
+	if !fg.hasModifier(FilterModifierAllIgnoreCase) {
+		return
+	}
+	fg.applyIgnoreCase()
+}
+
+func (fg *FilterGroup) hasModifier(modifier *FilterModifier) bool {
+	for _, group := range fg.groups {
+		if group.hasModifier(modifier) {
+			return true
+		}
+	}
+	for _, filter := range fg.filters {
+		if filter.modifier == modifier {
+			return true
+		}
+	}
+	return false
+}
+
+func (fg *FilterGroup) applyIgnoreCase() {
+	for _, group := range fg.groups {
+		group.applyIgnoreCase()
+	}
+	for _, filter := range fg.filters {
+		if filter.predicate.stringComparable {
+			if filter.modifier == nil {
+				filter.modifier = FilterModifierAllIgnoreCase
+			}
+		} else if filter.modifier == FilterModifierAllIgnoreCase {
+			filter.modifier = nil
+		}
+	}
+}
+
 func (fg *FilterGroup) IsEmpty() bool {
 	return len(fg.groups) == 0 && len(fg.filters) == 0
 }
 
+// Clone returns a deep copy of the group, the filled values of the copy are independent of the group
+func (fg *FilterGroup) Clone() *FilterGroup {
+	clone := &FilterGroup{logicOperator: fg.logicOperator}
+	if fg.groups != nil {
+		clone.groups = make([]*FilterGroup, 0, len(fg.groups))
+		for _, group := range fg.groups {
+			clone.groups = append(clone.groups, group.Clone())
+		}
+	}
+	if fg.filters != nil {
+		clone.filters = make([]*Filter, 0, len(fg.filters))
+		for _, filter := range fg.filters {
+			filterClone := *filter
+			clone.filters = append(clone.filters, &filterClone)
+		}
+	}
+	return clone
+}
+
 func (fg *FilterGroup) fillValues(values []any) {
 	remainingValues := values
 	if fg.groups != nil {
@@ -134,15 +204,17 @@ func (fg FilterGroup) String() string {
 }
 
 type Filter struct {
-	fieldName string
-	predicate *Predicate
-	modifier  *FilterModifier
-	values    []any
-	namedArgs []string
+	fieldName  string
+	fieldPath  FieldPath
+	predicate  *Predicate
+	modifier   *FilterModifier
+	values     []any
+	namedArgs  []string
+	collection bool
 }
 
 func NewFilter(fieldName string, predicate *Predicate, opts ...FilterOption) *Filter {
-	filter := &Filter{fieldName: fieldName, predicate: predicate}
+	filter := &Filter{fieldName: fieldName, fieldPath: NewFieldPath(fieldName), predicate: predicate}
 	for _, opt := range opts {
 		opt(filter)
 	}
@@ -183,6 +255,10 @@ func (f *Filter) FieldName() string {
 	return f.fieldName
 }
 
+func (f *Filter) FieldPath() FieldPath {
+	return f.fieldPath
+}
+
 func (f *Filter) Predicate() *Predicate {
 	return f.predicate
 }
@@ -191,6 +267,36 @@ func (f *Filter) FilterModifier() *FilterModifier {
 	return f.modifier
 }
 
+// IgnoreCase the filter is modified by IgnoreCase, or by AllIgnoreCase unless its bound value is not a string,
+// AllIgnoreCase does not apply to the values of other types, EX: the age in FindByNameAndAgeAllIgnoreCase.
+// The filters of the named args or the values not bound yet are decided by their predicates
+func (f *Filter) IgnoreCase() bool {
+	if f.modifier == FilterModifierIgnoreCase {
+		return true
+	}
+	return f.modifier == FilterModifierAllIgnoreCase && !f.nonStringValue()
+}
+
+// nonStringValue the value is bound, and it is neither a string nor a collection of strings
+func (f *Filter) nonStringValue() bool {
+	if f.namedArgs != nil || len(f.values) == 0 || f.values[0] == nil {
+		return false
+	}
+	valueType := reflect.TypeOf(f.values[0])
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+	if valueType.Kind() == reflect.Slice || valueType.Kind() == reflect.Array {
+		valueType = valueType.Elem()
+	}
+	return valueType.Kind() != reflect.String
+}
+
+// Collection the field is a collection, it is used by IsEmpty and IsNotEmpty
+func (f *Filter) Collection() bool {
+	return f.collection
+}
+
 func (f *Filter) Values() []any {
 	return f.values
 }
@@ -234,6 +340,12 @@ func WithFilterNamedArgs(namedArgs ...string) FilterOption {
 	}
 }
 
+func WithFilterCollection(collection bool) FilterOption {
+	return func(filter *Filter) {
+		filter.collection = collection
+	}
+}
+
 func WithFilterModifier(modifier *FilterModifier) FilterOption {
 	return func(filter *Filter) {
 		filter.modifier = modifier
@@ -241,8 +353,9 @@ func WithFilterModifier(modifier *FilterModifier) FilterOption {
 }
 
 type Predicate struct {
-	keywords []string
-	numArgs  int
+	keywords         []string
+	numArgs          int
+	stringComparable bool
 }
 
 func (p Predicate) String() string {
@@ -257,31 +370,35 @@ func (p *Predicate) NumArgs() int {
 	return p.numArgs
 }
 
+// StringComparable the predicate compares strings, AllIgnoreCase applies to the filters of these predicates
+func (p *Predicate) StringComparable() bool {
+	return p.stringComparable
+}
+
 var (
-	PredicateContains   = &Predicate{keywords: []string{"Contains"}, numArgs: 1}
-	PredicateStartsWith = &Predicate{keywords: []string{"StartsWith"}, numArgs: 1}
-	PredicateEndsWith   = &Predicate{keywords: []string{"EndsWith"}, numArgs: 1}
+	PredicateContains   = &Predicate{keywords: []string{"Contains"}, numArgs: 1, stringComparable: true}
+	PredicateStartsWith = &Predicate{keywords: []string{"StartsWith"}, numArgs: 1, stringComparable: true}
+	PredicateEndsWith   = &Predicate{keywords: []string{"EndsWith"}, numArgs: 1, stringComparable: true}
 	PredicateIsNull     = &Predicate{keywords: []string{"IsNull"}, numArgs: 0}
 	PredicateIsNotNull  = &Predicate{keywords: []string{"IsNotNull"}, numArgs: 0}
-	//PredicateIsEmpty is collection empty,
-	//If you want to express that the string is not empty, you can use 'Is',
-	//and then use an empty string as a parameter, the same below
+	//PredicateIsEmpty is string or collection NULL or empty,
+	//the field should be marked by WithFilterCollection if it is a collection, the same below
 	PredicateIsEmpty = &Predicate{keywords: []string{"IsEmpty"}, numArgs: 0}
-	//PredicateIsNotEmpty is collection not empty
+	//PredicateIsNotEmpty is string or collection neither NULL nor empty
 	PredicateIsNotEmpty = &Predicate{keywords: []string{"IsNotEmpty"}, numArgs: 0}
 	PredicateIsFalse    = &Predicate{keywords: []string{"IsFalse"}, numArgs: 0}
 	PredicateIsTrue     = &Predicate{keywords: []string{"IsTrue"}, numArgs: 0}
 	PredicateMatches    = &Predicate{keywords: []string{"Matches"}, numArgs: 1}
 	//PredicateBetween The BETWEEN operator is inclusive: begin and end values are included.
 	PredicateBetween = &Predicate{keywords: []string{"Between"}, numArgs: 2}
-	PredicateNotIn   = &Predicate{keywords: []string{"NotIn"}, numArgs: 1}
-	PredicateIn      = &Predicate{keywords: []string{"In"}, numArgs: 1}
+	PredicateNotIn   = &Predicate{keywords: []string{"NotIn"}, numArgs: 1, stringComparable: true}
+	PredicateIn      = &Predicate{keywords: []string{"In"}, numArgs: 1, stringComparable: true}
 	PredicateGT      = &Predicate{keywords: []string{"GT"}, numArgs: 1}
 	PredicateLT      = &Predicate{keywords: []string{"LT"}, numArgs: 1}
 	PredicateGTE     = &Predicate{keywords: []string{"GTE"}, numArgs: 1}
 	PredicateLTE     = &Predicate{keywords: []string{"LTE"}, numArgs: 1}
-	PredicateIsNot   = &Predicate{keywords: []string{"IsNot", "NotEquals", "NE"}, numArgs: 1}
-	PredicateIs      = &Predicate{keywords: []string{"Equals", "Is", "EQ", ""}, numArgs: 1}
+	PredicateIsNot   = &Predicate{keywords: []string{"IsNot", "NotEquals", "NE"}, numArgs: 1, stringComparable: true}
+	PredicateIs      = &Predicate{keywords: []string{"Equals", "Is", "EQ", ""}, numArgs: 1, stringComparable: true}
 )
 
 var Predicates = []*Predicate{
@@ -293,7 +410,7 @@ var Predicates = []*Predicate{
 
 type FilterModifier struct {
 	keywords []string
-	global   bool //wait support
+	global   bool //the modifier applies to all suitable filters, EX: AllIgnoreCase
 }
 
 func (p FilterModifier) String() string {
@@ -313,8 +430,9 @@ var (
 	FilterModifierAllIgnoreCase = &FilterModifier{keywords: []string{"AllIgnoreCase", "AllIC"}, global: true}
 )
 
+// FilterModifiers AllIgnoreCase precedes IgnoreCase, the parser matches the suffix in order
 var FilterModifiers = []*FilterModifier{
-	FilterModifierIgnoreCase, FilterModifierAllIgnoreCase,
+	FilterModifierAllIgnoreCase, FilterModifierIgnoreCase,
 }
 
 type LogicOperator string
@@ -0,0 +1,162 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Insertion is the rows of the Insert and Save subjects,
+// Save updates the rows conflicting on the conflict fields instead
+type Insertion struct {
+	fieldNames     []string
+	rows           [][]any
+	numRow         int
+	conflictFields []string
+}
+
+func NewInsertion(fieldNames []string, opts ...InsertionOption) *Insertion {
+	insertion := &Insertion{fieldNames: fieldNames}
+	for _, opt := range opts {
+		opt(insertion)
+	}
+	return insertion
+}
+
+// NewInsertionFromStruct the fields are derived from the struct like NewProjectionFromStruct
+func NewInsertionFromStruct(structOrType any, opts ...InsertionOption) (*Insertion, error) {
+	projection, err := NewProjectionFromStruct(structOrType)
+	if err != nil {
+		return nil, err
+	}
+	fieldNames := make([]string, 0, len(projection.Fields()))
+	for _, field := range projection.Fields() {
+		fieldNames = append(fieldNames, field.FieldName())
+	}
+	return NewInsertion(fieldNames, opts...), nil
+}
+
+// NewInsertionFromValues the fields are derived from the entity like NewInsertionFromStruct,
+// the entity is a struct, a pointer to struct or a slice of them, each entity is a row
+func NewInsertionFromValues(entities any, opts ...InsertionOption) (*Insertion, error) {
+	value := reflect.ValueOf(entities)
+	var entityValues []reflect.Value
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		entityValues = make([]reflect.Value, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			entityValues = append(entityValues, value.Index(i))
+		}
+	} else {
+		entityValues = []reflect.Value{value}
+	}
+	if len(entityValues) == 0 {
+		return nil, fmt.Errorf("insertion derive fail: no entity to insert")
+	}
+
+	structType := reflect.TypeOf(entities)
+	for structType.Kind() == reflect.Pointer || structType.Kind() == reflect.Slice || structType.Kind() == reflect.Array {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("insertion derive fail: [%v] is not a struct", structType)
+	}
+	structFields := structFieldsOf(structType, nil)
+	fieldNames := make([]string, 0, len(structFields))
+	for _, field := range structFields {
+		fieldNames = append(fieldNames, field.fieldName)
+	}
+
+	rows := make([][]any, 0, len(entityValues))
+	for index, entityValue := range entityValues {
+		for entityValue.Kind() == reflect.Pointer {
+			if entityValue.IsNil() {
+				return nil, fmt.Errorf("insertion derive fail: entity %d is nil", index)
+			}
+			entityValue = entityValue.Elem()
+		}
+		row := make([]any, 0, len(structFields))
+		for _, field := range structFields {
+			fieldValue, err := entityValue.FieldByIndexErr(field.index)
+			if err != nil {
+				//the embedded pointer is nil
+				row = append(row, nil)
+				continue
+			}
+			row = append(row, fieldValue.Interface())
+		}
+		rows = append(rows, row)
+	}
+	return NewInsertion(fieldNames, append([]InsertionOption{WithInsertionRows(rows...)}, opts...)...), nil
+}
+
+func (i *Insertion) FieldNames() []string {
+	return i.fieldNames
+}
+
+func (i *Insertion) Rows() [][]any {
+	return i.rows
+}
+
+// NumRow the number of rows, the rows have not been filled are counted too
+func (i *Insertion) NumRow() int {
+	if len(i.rows) > i.numRow {
+		return len(i.rows)
+	}
+	if i.numRow == 0 {
+		return 1
+	}
+	return i.numRow
+}
+
+func (i *Insertion) ConflictFields() []string {
+	return i.conflictFields
+}
+
+// FillRows fill the values of the rows, each row has a value for each field in order
+func (i *Insertion) FillRows(rows [][]any) error {
+	for index, row := range rows {
+		if len(row) != len(i.fieldNames) {
+			return fmt.Errorf("expected %d values, but actual %d values in row %d", len(i.fieldNames), len(row), index)
+		}
+	}
+	i.rows = rows
+	return nil
+}
+
+func (i Insertion) String() string {
+	builder := strings.Builder{}
+	builder.Grow(64)
+	builder.WriteRune('(')
+	builder.WriteString(strings.Join(i.fieldNames, ", "))
+	builder.WriteRune(')')
+	if len(i.rows) > 0 {
+		builder.WriteString(fmt.Sprintf(" %#v", i.rows))
+	}
+	if len(i.conflictFields) > 0 {
+		builder.WriteString(" On Conflict (")
+		builder.WriteString(strings.Join(i.conflictFields, ", "))
+		builder.WriteRune(')')
+	}
+	return builder.String()
+}
+
+type InsertionOption func(insertion *Insertion)
+
+func WithInsertionRows(rows ...[]any) InsertionOption {
+	return func(insertion *Insertion) {
+		insertion.rows = rows
+	}
+}
+
+// WithInsertionNumRow the number of rows to insert, it is used when the rows are filled after translation
+func WithInsertionNumRow(numRow int) InsertionOption {
+	return func(insertion *Insertion) {
+		insertion.numRow = numRow
+	}
+}
+
+func WithInsertionConflictFields(fieldNames ...string) InsertionOption {
+	return func(insertion *Insertion) {
+		insertion.conflictFields = fieldNames
+	}
+}
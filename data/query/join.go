@@ -0,0 +1,98 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Join joins the table of the nested field, the table is aliased by the path,
+// EX: the filter on Customer_Name is translated to the column name of the table joined as customer
+type Join struct {
+	path       FieldPath
+	table      Table
+	joinType   JoinType
+	conditions []*JoinCondition
+}
+
+// NewJoin the path is the nested field joined, EX: Customer, Customer_Address, default is inner join
+func NewJoin(path string, table Table, conditions []*JoinCondition, opts ...JoinOption) *Join {
+	join := &Join{path: NewFieldPath(path), table: table, joinType: JoinTypeInner, conditions: conditions}
+	for _, opt := range opts {
+		opt(join)
+	}
+	return join
+}
+
+func (j *Join) Path() FieldPath {
+	return j.path
+}
+
+func (j *Join) Table() Table {
+	return j.table
+}
+
+func (j *Join) JoinType() JoinType {
+	return j.joinType
+}
+
+func (j *Join) Conditions() []*JoinCondition {
+	return j.conditions
+}
+
+func (j Join) String() string {
+	builder := strings.Builder{}
+	builder.Grow(64)
+	builder.WriteString(j.joinType.String())
+	builder.WriteString(" Join ")
+	builder.WriteString(j.path.String())
+	builder.WriteString(" On ")
+	for i, condition := range j.conditions {
+		if i > 0 {
+			builder.WriteString(" And ")
+		}
+		builder.WriteString(condition.String())
+	}
+	return builder.String()
+}
+
+type JoinOption func(join *Join)
+
+func WithJoinType(joinType JoinType) JoinOption {
+	return func(join *Join) {
+		join.joinType = joinType
+	}
+}
+
+// JoinCondition the field of the parent equals to the field of the joined table,
+// the parent is the table of the parent path, or the table of the query if the path is not nested
+type JoinCondition struct {
+	fieldName       string
+	targetFieldName string
+}
+
+func NewJoinCondition(fieldName string, targetFieldName string) *JoinCondition {
+	return &JoinCondition{fieldName: fieldName, targetFieldName: targetFieldName}
+}
+
+func (c *JoinCondition) FieldName() string {
+	return c.fieldName
+}
+
+func (c *JoinCondition) TargetFieldName() string {
+	return c.targetFieldName
+}
+
+func (c JoinCondition) String() string {
+	return fmt.Sprintf("%s = %s", c.fieldName, c.targetFieldName)
+}
+
+type JoinType string
+
+func (j JoinType) String() string {
+	return string(j)
+}
+
+const (
+	JoinTypeInner JoinType = "Inner"
+	JoinTypeLeft  JoinType = "Left"
+)
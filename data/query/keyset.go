@@ -0,0 +1,106 @@
+package query
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// KeysetRequest is the keyset (seek) pager, it carries the sort values of the last row of the previous page,
+// the next page starts after the row instead of skipping an offset,
+// the keys are in the order of the sorts of the query, no keys means the first page
+type KeysetRequest struct {
+	pageSize int
+	keys     []any
+}
+
+func NewKeysetRequest(pageSize int, keys ...any) *KeysetRequest {
+	return &KeysetRequest{pageSize: pageSize, keys: keys}
+}
+
+func (k KeysetRequest) String() string {
+	if len(k.keys) == 0 {
+		return fmt.Sprintf("Limit %d", k.pageSize)
+	}
+	return fmt.Sprintf("After %#v Limit %d", k.keys, k.pageSize)
+}
+
+// Page the keyset pager does not know the page number, it is always 1
+func (k *KeysetRequest) Page() int {
+	return 1
+}
+
+func (k *KeysetRequest) PageSize() int {
+	return k.pageSize
+}
+
+func (k *KeysetRequest) SetPageSize(pageSize int) {
+	k.pageSize = pageSize
+}
+
+// Offset the keyset pager seeks by the keys, it is always 0
+func (k *KeysetRequest) Offset() int {
+	return 0
+}
+
+// SearchCount the keyset pager does not count the total
+func (k *KeysetRequest) SearchCount() bool {
+	return false
+}
+
+func (k *KeysetRequest) Keys() []any {
+	return k.keys
+}
+
+func (k *KeysetRequest) SetKeys(keys ...any) {
+	k.keys = keys
+}
+
+// EncodeCursor encodes the sort values of the last row to an opaque token for APIs
+func EncodeCursor(keys ...any) (string, error) {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return "", fmt.Errorf("encode cursor fail: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes the token returned by EncodeCursor, an empty token means the first page,
+// the integer numbers are decoded as int64 and the others as float64,
+// the values such as time.Time are decoded as their JSON form
+func DecodeCursor(token string) (keys []any, err error) {
+	if len(token) == 0 {
+		return
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("decode cursor fail: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&keys); err != nil {
+		return nil, fmt.Errorf("decode cursor fail: %w", err)
+	}
+	for i, key := range keys {
+		number, ok := key.(json.Number)
+		if !ok {
+			continue
+		}
+		if intValue, intErr := number.Int64(); intErr == nil {
+			keys[i] = intValue
+		} else if keys[i], err = number.Float64(); err != nil {
+			return nil, fmt.Errorf("decode cursor fail: %w", err)
+		}
+	}
+	return
+}
+
+// NewKeysetRequestFromCursor returns the keyset pager starting after the row of the cursor
+func NewKeysetRequestFromCursor(pageSize int, token string) (*KeysetRequest, error) {
+	keys, err := DecodeCursor(token)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeysetRequest(pageSize, keys...), nil
+}
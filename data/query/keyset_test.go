@@ -0,0 +1,54 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []any
+		wantKeys []any
+	}{
+		{
+			name:     "first page",
+			keys:     nil,
+			wantKeys: []any{},
+		},
+		{
+			name:     "integer and string",
+			keys:     []any{int64(9007199254740993), "2022-10-01T00:00:00Z"},
+			wantKeys: []any{int64(9007199254740993), "2022-10-01T00:00:00Z"},
+		},
+		{
+			name:     "float bool and nil",
+			keys:     []any{1.5, true, nil},
+			wantKeys: []any{1.5, true, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := EncodeCursor(tt.keys...)
+			if err != nil {
+				t.Errorf("EncodeCursor() error = %v", err)
+				return
+			}
+			gotKeys, err := DecodeCursor(token)
+			if err != nil {
+				t.Errorf("DecodeCursor() error = %v", err)
+				return
+			}
+			if len(gotKeys) == 0 && len(tt.wantKeys) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(gotKeys, tt.wantKeys) {
+				t.Errorf("DecodeCursor() \nactual = %#v, \nexpect = %#v", gotKeys, tt.wantKeys)
+			}
+		})
+	}
+
+	if _, err := DecodeCursor("not a cursor!"); err == nil {
+		t.Errorf("DecodeCursor() expected error for an invalid token")
+	}
+}
@@ -54,3 +54,95 @@ func (p *PageRequest) SearchCount() bool {
 func (p *PageRequest) SetSearchCount(searchCount bool) {
 	p.searchCount = searchCount
 }
+
+// Page is the content of the page requested by the pager with navigation metadata,
+// the total is unknown if it has not been counted
+type Page[T any] struct {
+	content     []T
+	page        int
+	pageSize    int
+	total       int64
+	counted     bool
+	hasPrevious bool
+}
+
+// NewPage returns the page with the total counted
+func NewPage[T any](content []T, pager Pager, total int64) *Page[T] {
+	page := newPage(content, pager)
+	page.total = total
+	page.counted = true
+	return page
+}
+
+// NewPageWithoutCount returns the page without counting the total, HasNext is inferred from the size of the content
+func NewPageWithoutCount[T any](content []T, pager Pager) *Page[T] {
+	return newPage(content, pager)
+}
+
+func newPage[T any](content []T, pager Pager) *Page[T] {
+	page := &Page[T]{content: content, page: 1, pageSize: len(content)}
+	if pager == nil {
+		return page
+	}
+	page.page = pager.Page()
+	page.pageSize = pager.PageSize()
+	if keyset, ok := pager.(*KeysetRequest); ok {
+		page.hasPrevious = len(keyset.Keys()) > 0
+	} else {
+		page.hasPrevious = pager.Offset() > 0
+	}
+	return page
+}
+
+func (p *Page[T]) Content() []T {
+	return p.content
+}
+
+func (p *Page[T]) Page() int {
+	return p.page
+}
+
+func (p *Page[T]) PageSize() int {
+	return p.pageSize
+}
+
+// Total the total elements of all pages, it is -1 if it has not been counted
+func (p *Page[T]) Total() int64 {
+	if !p.counted {
+		return -1
+	}
+	return p.total
+}
+
+// TotalPages the number of pages, it is -1 if the total has not been counted
+func (p *Page[T]) TotalPages() int {
+	if !p.counted {
+		return -1
+	}
+	if p.pageSize <= 0 {
+		return 1
+	}
+	return int((p.total + int64(p.pageSize) - 1) / int64(p.pageSize))
+}
+
+func (p *Page[T]) Counted() bool {
+	return p.counted
+}
+
+func (p *Page[T]) HasNext() bool {
+	if !p.counted {
+		return p.pageSize > 0 && len(p.content) >= p.pageSize
+	}
+	return p.page < p.TotalPages()
+}
+
+func (p *Page[T]) HasPrevious() bool {
+	return p.hasPrevious
+}
+
+func (p Page[T]) String() string {
+	if !p.counted {
+		return fmt.Sprintf("Page %d of unknown, %d elements", p.page, len(p.content))
+	}
+	return fmt.Sprintf("Page %d of %d, %d elements of %d", p.page, p.TotalPages(), len(p.content), p.total)
+}
@@ -0,0 +1,140 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type Projection struct {
+	fields []*ProjectionField
+}
+
+func NewProjection(fieldNames ...string) *Projection {
+	fields := make([]*ProjectionField, 0, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		fields = append(fields, NewProjectionField(fieldName))
+	}
+	return &Projection{fields: fields}
+}
+
+func NewProjectionWithFields(fields []*ProjectionField) *Projection {
+	return &Projection{fields: fields}
+}
+
+// NewProjectionFromStruct derive the projection from the exported fields of the struct(or pointer to struct),
+// the field is named by the db tag if present, fields tagged with db:"-" are skipped,
+// the fields of embedded structs are promoted
+func NewProjectionFromStruct(structOrType any) (*Projection, error) {
+	structType, ok := structOrType.(reflect.Type)
+	if !ok {
+		structType = reflect.TypeOf(structOrType)
+	}
+	for structType != nil && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("projection derive fail: [%v] is not a struct", structType)
+	}
+	return &Projection{fields: projectionFieldsOf(structType)}, nil
+}
+
+func projectionFieldsOf(structType reflect.Type) []*ProjectionField {
+	structFields := structFieldsOf(structType, nil)
+	fields := make([]*ProjectionField, 0, len(structFields))
+	for _, structField := range structFields {
+		fields = append(fields, NewProjectionField(structField.fieldName))
+	}
+	return fields
+}
+
+// structField is the field of the struct mapped to the query field, the index is the index sequence for FieldByIndex
+type structField struct {
+	fieldName string
+	index     []int
+}
+
+func structFieldsOf(structType reflect.Type, parentIndex []int) []structField {
+	fields := make([]structField, 0, structType.NumField())
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		index := append(append(make([]int, 0, len(parentIndex)+1), parentIndex...), i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && len(tag) == 0 && fieldType.Kind() == reflect.Struct {
+			fields = append(fields, structFieldsOf(fieldType, index)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if len(tag) > 0 {
+			fields = append(fields, structField{fieldName: tag, index: index})
+		} else {
+			fields = append(fields, structField{fieldName: field.Name, index: index})
+		}
+	}
+	return fields
+}
+
+func (p *Projection) Fields() []*ProjectionField {
+	return p.fields
+}
+
+func (p *Projection) IsEmpty() bool {
+	return len(p.fields) == 0
+}
+
+func (p Projection) String() string {
+	builder := strings.Builder{}
+	builder.Grow(64)
+	for i, field := range p.fields {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(field.String())
+	}
+	return builder.String()
+}
+
+type ProjectionField struct {
+	fieldName string
+	alias     string
+}
+
+func NewProjectionField(fieldName string, opts ...ProjectionFieldOption) *ProjectionField {
+	field := &ProjectionField{fieldName: fieldName}
+	for _, opt := range opts {
+		opt(field)
+	}
+	return field
+}
+
+func (f *ProjectionField) FieldName() string {
+	return f.fieldName
+}
+
+func (f *ProjectionField) Alias() string {
+	return f.alias
+}
+
+func (f ProjectionField) String() string {
+	if len(f.alias) == 0 {
+		return f.fieldName
+	}
+	return f.fieldName + " AS " + f.alias
+}
+
+type ProjectionFieldOption func(field *ProjectionField)
+
+func WithProjectionFieldAlias(alias string) ProjectionFieldOption {
+	return func(field *ProjectionField) {
+		field.alias = alias
+	}
+}
@@ -6,12 +6,18 @@ import (
 )
 
 type Query struct {
-	//TODO 要添加projection
 	table               Table
+	joins               []*Join
 	subject             *Subject
+	projection          *Projection
+	aggregateField      string
+	assignments         []*Assignment
+	insertion           *Insertion
 	subjectModifier     *SubjectModifier
 	subjectModifierArgs map[SubjectModifierArg]any
 	filterGroup         *FilterGroup
+	groupBy             []string
+	having              *FilterGroup
 	sorts               []*Sort
 	pager               Pager
 }
@@ -29,10 +35,17 @@ func New(subject *Subject, opts ...Option) *Query {
 func (q *Query) With(opts ...Option) *Query {
 	newQuery := &Query{
 		table:               q.table,
+		joins:               q.joins,
 		subject:             q.subject,
+		projection:          q.projection,
+		aggregateField:      q.aggregateField,
+		assignments:         q.assignments,
+		insertion:           q.insertion,
 		subjectModifier:     q.subjectModifier,
 		subjectModifierArgs: q.subjectModifierArgs,
 		filterGroup:         q.filterGroup,
+		groupBy:             q.groupBy,
+		having:              q.having,
 		sorts:               q.sorts,
 		pager:               q.pager,
 	}
@@ -46,10 +59,33 @@ func (q *Query) Table() Table {
 	return q.table
 }
 
+func (q *Query) Joins() []*Join {
+	return q.joins
+}
+
 func (q *Query) Subject() *Subject {
 	return q.subject
 }
 
+func (q *Query) Projection() *Projection {
+	return q.projection
+}
+
+// AggregateField the target field of the aggregate subject, EX: Amount of SumAmountByUserId
+func (q *Query) AggregateField() string {
+	return q.aggregateField
+}
+
+// Assignments the set clause of the Update subject, EX: Status of UpdateStatusById
+func (q *Query) Assignments() []*Assignment {
+	return q.assignments
+}
+
+// Insertion the rows of the Insert and Save subjects
+func (q *Query) Insertion() *Insertion {
+	return q.insertion
+}
+
 func (q *Query) SubjectModifier() *SubjectModifier {
 	return q.subjectModifier
 }
@@ -62,6 +98,16 @@ func (q *Query) FilterGroup() *FilterGroup {
 	return q.filterGroup
 }
 
+func (q *Query) GroupBy() []string {
+	return q.groupBy
+}
+
+// Having filters the groups, the field named by the aggregate subject refers to the aggregate value,
+// EX: Count of CountGroupByStatus
+func (q *Query) Having() *FilterGroup {
+	return q.having
+}
+
 func (q *Query) Sorts() []*Sort {
 	return q.sorts
 }
@@ -70,14 +116,90 @@ func (q *Query) Pager() Pager {
 	return q.pager
 }
 
+// Bind returns the query filled with the values, the query is reused by binding it for each execution,
+// the values of the assignments precede the values of the filters
+func (q *Query) Bind(values ...any) (*Query, error) {
+	numValue := len(q.assignments)
+	if q.filterGroup != nil {
+		numValue += q.filterGroup.NumValue()
+	}
+	if numValue != len(values) {
+		return nil, fmt.Errorf("bind query fail: expected %d values, but actual %d values", numValue, len(values))
+	}
+	newQuery := q.With()
+	if len(q.assignments) > 0 {
+		assignments := make([]*Assignment, 0, len(q.assignments))
+		for i, assignment := range q.assignments {
+			assignments = append(assignments, NewAssignment(assignment.FieldName(), WithAssignmentValue(values[i])))
+		}
+		newQuery.assignments = assignments
+		values = values[len(q.assignments):]
+	}
+	if q.filterGroup != nil {
+		newQuery.filterGroup = q.filterGroup.Clone()
+		newQuery.filterGroup.fillValues(values)
+	}
+	return newQuery, nil
+}
+
+// WithSortSpec returns the query sorted by the dynamic sorts merged with the static sorts,
+// the dynamic sorts are validated against the allowed fields if any
+func (q *Query) WithSortSpec(spec *SortSpec, allowedFieldNames ...string) (*Query, error) {
+	if spec == nil {
+		return q, nil
+	}
+	if len(allowedFieldNames) > 0 {
+		if err := spec.Validate(allowedFieldNames...); err != nil {
+			return nil, err
+		}
+	}
+	return q.With(WithSorts(spec.Merge(q.sorts))), nil
+}
+
 type Option func(q *Query)
 
+func WithSubject(subject *Subject) Option {
+	return func(q *Query) {
+		q.subject = subject
+	}
+}
+
 func WithTable(table Table) Option {
 	return func(q *Query) {
 		q.table = table
 	}
 }
 
+func WithJoins(joins []*Join) Option {
+	return func(q *Query) {
+		q.joins = joins
+	}
+}
+
+func WithProjection(projection *Projection) Option {
+	return func(q *Query) {
+		q.projection = projection
+	}
+}
+
+func WithAggregateField(fieldName string) Option {
+	return func(q *Query) {
+		q.aggregateField = fieldName
+	}
+}
+
+func WithAssignments(assignments []*Assignment) Option {
+	return func(q *Query) {
+		q.assignments = assignments
+	}
+}
+
+func WithInsertion(insertion *Insertion) Option {
+	return func(q *Query) {
+		q.insertion = insertion
+	}
+}
+
 func WithSubjectModifier(modifier *SubjectModifier) Option {
 	return func(q *Query) {
 		q.subjectModifier = modifier
@@ -96,6 +218,18 @@ func WithFilterGroup(filterGroup *FilterGroup) Option {
 	}
 }
 
+func WithGroupBy(fieldNames []string) Option {
+	return func(q *Query) {
+		q.groupBy = fieldNames
+	}
+}
+
+func WithHaving(having *FilterGroup) Option {
+	return func(q *Query) {
+		q.having = having
+	}
+}
+
 func WithSorts(sorts []*Sort) Option {
 	return func(q *Query) {
 		q.sorts = sorts
@@ -123,11 +257,51 @@ func (q Query) String() string {
 		builder.WriteString(fmt.Sprintf("%v", q.subjectModifierArgs))
 	}
 
+	if len(q.aggregateField) > 0 {
+		builder.WriteRune(' ')
+		builder.WriteString(q.aggregateField)
+	}
+
+	if len(q.assignments) > 0 {
+		builder.WriteString(" SET ")
+		for i, assignment := range q.assignments {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			builder.WriteString(assignment.String())
+		}
+	}
+
+	if q.insertion != nil {
+		builder.WriteRune(' ')
+		builder.WriteString(q.insertion.String())
+	}
+
+	if q.projection != nil && !q.projection.IsEmpty() {
+		builder.WriteRune(' ')
+		builder.WriteString(q.projection.String())
+	}
+
+	for _, join := range q.joins {
+		builder.WriteRune(' ')
+		builder.WriteString(join.String())
+	}
+
 	if q.filterGroup != nil {
 		builder.WriteString(" WHERE ")
 		builder.WriteString(q.filterGroup.String())
 	}
 
+	if len(q.groupBy) > 0 {
+		builder.WriteString(" Group By ")
+		builder.WriteString(strings.Join(q.groupBy, ", "))
+	}
+
+	if q.having != nil {
+		builder.WriteString(" HAVING ")
+		builder.WriteString(q.having.String())
+	}
+
 	if len(q.sorts) > 0 {
 		builder.WriteString(" Order By ")
 		for i, sort := range q.sorts {
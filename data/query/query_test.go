@@ -0,0 +1,116 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuery_Bind(t *testing.T) {
+	q := New(
+		SubjectUpdate,
+		WithAssignments(NewAssignments("Status")),
+		WithFilterGroup(NewFilterGroup([]*FilterGroup{
+			NewFilterGroupWithFilters([]*Filter{
+				NewFilter("Id", PredicateIs),
+				NewFilter("Age", PredicateBetween),
+			}, LogicOperatorAnd),
+			NewFilterGroupWithFilters([]*Filter{
+				NewFilter("Email", PredicateIsNull),
+				NewFilter("Name", PredicateIs),
+			}, LogicOperatorAnd),
+		}, LogicOperatorOr)),
+	)
+
+	bound, err := q.Bind(1, int64(2), 18, 30, "Lily")
+	if err != nil {
+		t.Errorf("Bind() error = %v", err)
+		return
+	}
+	if got := bound.Assignments()[0].Value(); got != 1 {
+		t.Errorf("Bind() assignment value = %v, want 1", got)
+	}
+	groups := bound.FilterGroup().Groups()
+	gotValues := [][]any{
+		groups[0].Filters()[0].Values(),
+		groups[0].Filters()[1].Values(),
+		groups[1].Filters()[0].Values(),
+		groups[1].Filters()[1].Values(),
+	}
+	wantValues := [][]any{{int64(2)}, {18, 30}, nil, {"Lily"}}
+	if !reflect.DeepEqual(gotValues, wantValues) {
+		t.Errorf("Bind() \nactual = %#v, \nexpect = %#v", gotValues, wantValues)
+	}
+
+	if q.Assignments()[0].Value() != nil || q.FilterGroup().Groups()[0].Filters()[0].Values() != nil {
+		t.Errorf("Bind() changed the values of the origin query")
+	}
+
+	if _, err = q.Bind(1, 2); err == nil {
+		t.Errorf("Bind() expected error for the mismatched values")
+	}
+}
+
+func TestNewInsertionFromValues(t *testing.T) {
+	type Base struct {
+		Id int64 `db:"id"`
+	}
+	type User struct {
+		*Base
+		Name     string
+		Password string `db:"-"`
+		age      int
+	}
+	tests := []struct {
+		name           string
+		entities       any
+		wantFieldNames []string
+		wantRows       [][]any
+		wantErr        bool
+	}{
+		{
+			name:           "pointer to struct",
+			entities:       &User{Base: &Base{Id: 1}, Name: "Lily", Password: "secret"},
+			wantFieldNames: []string{"id", "Name"},
+			wantRows:       [][]any{{int64(1), "Lily"}},
+		},
+		{
+			name:           "slice with nil embedded pointer",
+			entities:       []User{{Base: &Base{Id: 1}, Name: "Lily"}, {Name: "Lucy"}},
+			wantFieldNames: []string{"id", "Name"},
+			wantRows:       [][]any{{int64(1), "Lily"}, {nil, "Lucy"}},
+		},
+		{
+			name:     "empty slice",
+			entities: []*User{},
+			wantErr:  true,
+		},
+		{
+			name:     "nil entity",
+			entities: []*User{nil},
+			wantErr:  true,
+		},
+		{
+			name:     "not struct",
+			entities: []int{1},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			insertion, err := NewInsertionFromValues(tt.entities)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewInsertionFromValues() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(insertion.FieldNames(), tt.wantFieldNames) {
+				t.Errorf("NewInsertionFromValues() \nactual = %v, \nexpect = %v", insertion.FieldNames(), tt.wantFieldNames)
+			}
+			if !reflect.DeepEqual(insertion.Rows(), tt.wantRows) {
+				t.Errorf("NewInsertionFromValues() \nactual = %#v, \nexpect = %#v", insertion.Rows(), tt.wantRows)
+			}
+		})
+	}
+}
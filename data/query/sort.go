@@ -1,14 +1,20 @@
 package query
 
-import "fmt"
+import (
+	"fmt"
+	"github.com/huandu/xstrings"
+	"strings"
+	"unicode"
+)
 
 type Sort struct {
 	fieldName string
+	fieldPath FieldPath
 	direction Direction
 }
 
 func NewSort(fieldName string, direction Direction) *Sort {
-	return &Sort{fieldName: fieldName, direction: direction}
+	return &Sort{fieldName: fieldName, fieldPath: NewFieldPath(fieldName), direction: direction}
 }
 
 func (s Sort) String() string {
@@ -19,6 +25,10 @@ func (s *Sort) FieldName() string {
 	return s.fieldName
 }
 
+func (s *Sort) FieldPath() FieldPath {
+	return s.fieldPath
+}
+
 func (s *Sort) Direction() Direction {
 	return s.direction
 }
@@ -33,3 +43,145 @@ const (
 	DirectionDesc Direction = "Desc"
 	DirectionAsc  Direction = "Asc"
 )
+
+// SortSpec is the dynamic sorts argument of the repository method,
+// it is appended to the static sorts of the method name, or overrides them if it is not empty
+type SortSpec struct {
+	sorts    []*Sort
+	override bool
+}
+
+func NewSortSpec(sorts []*Sort, opts ...SortSpecOption) *SortSpec {
+	spec := &SortSpec{sorts: sorts}
+	for _, opt := range opts {
+		opt(spec)
+	}
+	return spec
+}
+
+// ParseSortSpec parses the common sort parameter, EX: name,-createdAt,+customer.name,
+// the prefix - means descending, + or none means ascending,
+// the field is converted to the field name, EX: createdAt and created_at are CreatedAt, customer.name is Customer_Name
+func ParseSortSpec(str string, opts ...SortSpecOption) (*SortSpec, error) {
+	sorts, err := ParseSorts(str)
+	if err != nil {
+		return nil, err
+	}
+	return NewSortSpec(sorts, opts...), nil
+}
+
+// ParseSorts parses the sort parameter like ParseSortSpec
+func ParseSorts(str string) (sorts []*Sort, err error) {
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		direction := DirectionAsc
+		switch item[0] {
+		case '-':
+			direction = DirectionDesc
+			item = item[1:]
+		case '+':
+			item = item[1:]
+		}
+		var fieldName string
+		fieldName, err = sortFieldName(item)
+		if err != nil {
+			return nil, err
+		}
+		sorts = append(sorts, NewSort(fieldName, direction))
+	}
+	return
+}
+
+func sortFieldName(str string) (string, error) {
+	parts := strings.Split(str, ".")
+	for i, part := range parts {
+		if len(part) == 0 {
+			return "", fmt.Errorf("sort parse fail: invalid field [%s]", str)
+		}
+		for _, r := range part {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+				return "", fmt.Errorf("sort parse fail: invalid field [%s]", str)
+			}
+		}
+		if strings.Contains(part, "_") {
+			parts[i] = xstrings.ToCamelCase(part)
+		} else {
+			parts[i] = xstrings.FirstRuneToUpper(part)
+		}
+	}
+	return strings.Join(parts, "_"), nil
+}
+
+func (s *SortSpec) Sorts() []*Sort {
+	return s.sorts
+}
+
+func (s *SortSpec) Override() bool {
+	return s.override
+}
+
+// Validate returns error if the field of any sort is not allowed
+func (s *SortSpec) Validate(allowedFieldNames ...string) error {
+	for _, sort := range s.sorts {
+		allowed := false
+		for _, fieldName := range allowedFieldNames {
+			if sort.FieldName() == fieldName {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("sort validate fail: field [%s] is not allowed to sort", sort.FieldName())
+		}
+	}
+	return nil
+}
+
+// Merge merges the sorts with the static sorts,
+// the static sorts take precedence and the dynamic sorts on the same fields are ignored, unless overrides
+func (s *SortSpec) Merge(staticSorts []*Sort) []*Sort {
+	if len(s.sorts) == 0 {
+		return staticSorts
+	}
+	if s.override || len(staticSorts) == 0 {
+		return s.sorts
+	}
+	sorts := make([]*Sort, 0, len(staticSorts)+len(s.sorts))
+	sorts = append(sorts, staticSorts...)
+	for _, sort := range s.sorts {
+		duplicated := false
+		for _, staticSort := range staticSorts {
+			if sort.FieldName() == staticSort.FieldName() {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			sorts = append(sorts, sort)
+		}
+	}
+	return sorts
+}
+
+func (s SortSpec) String() string {
+	sortStrs := make([]string, 0, len(s.sorts))
+	for _, sort := range s.sorts {
+		sortStrs = append(sortStrs, sort.String())
+	}
+	if s.override {
+		return "Override " + strings.Join(sortStrs, ", ")
+	}
+	return strings.Join(sortStrs, ", ")
+}
+
+type SortSpecOption func(spec *SortSpec)
+
+// WithSortSpecOverride the sorts override the static sorts instead of being appended
+func WithSortSpecOverride(override bool) SortSpecOption {
+	return func(spec *SortSpec) {
+		spec.override = override
+	}
+}
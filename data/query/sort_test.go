@@ -0,0 +1,137 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSorts(t *testing.T) {
+	tests := []struct {
+		name      string
+		str       string
+		wantSorts []*Sort
+		wantErr   bool
+	}{
+		{
+			name:      "empty",
+			str:       "",
+			wantSorts: nil,
+			wantErr:   false,
+		},
+		{
+			name: "asc and desc",
+			str:  "name,-createdAt, +id",
+			wantSorts: []*Sort{
+				NewSort("Name", DirectionAsc),
+				NewSort("CreatedAt", DirectionDesc),
+				NewSort("Id", DirectionAsc),
+			},
+			wantErr: false,
+		},
+		{
+			name: "snake case and nested",
+			str:  "-created_at,customer.name",
+			wantSorts: []*Sort{
+				NewSort("CreatedAt", DirectionDesc),
+				NewSort("Customer_Name", DirectionAsc),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "invalid field",
+			str:     "name;drop table user",
+			wantErr: true,
+		},
+		{
+			name:    "empty nested field",
+			str:     "customer.",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSorts, err := ParseSorts(tt.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSorts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotSorts, tt.wantSorts) {
+				t.Errorf("ParseSorts() \nactual = %v, \nexpect = %v", gotSorts, tt.wantSorts)
+			}
+		})
+	}
+}
+
+func TestQuery_WithSortSpec(t *testing.T) {
+	staticSorts := []*Sort{NewSort("Age", DirectionDesc)}
+	tests := []struct {
+		name          string
+		spec          *SortSpec
+		allowedFields []string
+		wantSorts     []*Sort
+		wantErr       bool
+	}{
+		{
+			name:      "nil spec",
+			spec:      nil,
+			wantSorts: staticSorts,
+			wantErr:   false,
+		},
+		{
+			name: "append",
+			spec: NewSortSpec([]*Sort{NewSort("Age", DirectionAsc), NewSort("Name", DirectionAsc)}),
+			wantSorts: []*Sort{
+				NewSort("Age", DirectionDesc),
+				NewSort("Name", DirectionAsc),
+			},
+			wantErr: false,
+		},
+		{
+			name:      "override",
+			spec:      NewSortSpec([]*Sort{NewSort("Name", DirectionAsc)}, WithSortSpecOverride(true)),
+			wantSorts: []*Sort{NewSort("Name", DirectionAsc)},
+			wantErr:   false,
+		},
+		{
+			name:      "override by empty",
+			spec:      NewSortSpec(nil, WithSortSpecOverride(true)),
+			wantSorts: staticSorts,
+			wantErr:   false,
+		},
+		{
+			name:          "allowed",
+			spec:          NewSortSpec([]*Sort{NewSort("Name", DirectionAsc)}),
+			allowedFields: []string{"Name", "CreatedAt"},
+			wantSorts: []*Sort{
+				NewSort("Age", DirectionDesc),
+				NewSort("Name", DirectionAsc),
+			},
+			wantErr: false,
+		},
+		{
+			name:          "not allowed",
+			spec:          NewSortSpec([]*Sort{NewSort("Password", DirectionAsc)}),
+			allowedFields: []string{"Name", "CreatedAt"},
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := New(SubjectFind, WithSorts(staticSorts))
+			gotQuery, err := q.WithSortSpec(tt.spec, tt.allowedFields...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WithSortSpec() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(gotQuery.Sorts(), tt.wantSorts) {
+				t.Errorf("WithSortSpec() \nactual = %v, \nexpect = %v", gotQuery.Sorts(), tt.wantSorts)
+			}
+			if !reflect.DeepEqual(q.Sorts(), staticSorts) {
+				t.Errorf("WithSortSpec() changed the sorts of the origin query")
+			}
+		})
+	}
+}
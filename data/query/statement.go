@@ -0,0 +1,132 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Statement is the result of translation, it carries the SQL and the bindings of the placeholders in order.
+// Each binding is either a value, it is nil if the value of the filter has not been filled,
+// or a name whose value should be bound by BindNamedArgs. The placeholders of both are numbered by the same counter.
+type Statement struct {
+	sql      string
+	bindings []Binding
+}
+
+func NewStatement(sql string, bindings []Binding) *Statement {
+	return &Statement{sql: sql, bindings: bindings}
+}
+
+func (s *Statement) SQL() string {
+	return s.sql
+}
+
+// Bindings the bindings of all the placeholders in placeholder order
+func (s *Statement) Bindings() []Binding {
+	return s.bindings
+}
+
+// Args the values of the bindings which are not named in placeholder order
+func (s *Statement) Args() (args []any) {
+	for _, binding := range s.bindings {
+		if !binding.IsNamed() {
+			args = append(args, binding.value)
+		}
+	}
+	return
+}
+
+// NamedArgs the names of the named bindings in placeholder order
+func (s *Statement) NamedArgs() (names []string) {
+	for _, binding := range s.bindings {
+		if binding.IsNamed() {
+			names = append(names, binding.name)
+		}
+	}
+	return
+}
+
+// BindNamedArgs returns the args of all the placeholders in placeholder order,
+// the named bindings take the values of their names
+func (s *Statement) BindNamedArgs(values map[string]any) (args []any, err error) {
+	args = make([]any, 0, len(s.bindings))
+	for _, binding := range s.bindings {
+		if !binding.IsNamed() {
+			args = append(args, binding.value)
+			continue
+		}
+		value, ok := values[binding.name]
+		if !ok {
+			err = fmt.Errorf("bind named args fail: missing the value of [%s]", binding.name)
+			return
+		}
+		args = append(args, value)
+	}
+	return
+}
+
+func (s *Statement) IsEmpty() bool {
+	return len(s.sql) == 0
+}
+
+func (s Statement) String() string {
+	builder := strings.Builder{}
+	builder.Grow(len(s.sql) + 64)
+	builder.WriteString(s.sql)
+	if args := s.Args(); len(args) > 0 {
+		builder.WriteString(fmt.Sprintf(" %#v", args))
+	}
+	if names := s.NamedArgs(); len(names) > 0 {
+		builder.WriteString(" :")
+		builder.WriteString(strings.Join(names, ", :"))
+	}
+	return builder.String()
+}
+
+// Binding is the argument of a placeholder, it has either a name or a value
+type Binding struct {
+	name  string
+	value any
+}
+
+func NewValueBinding(value any) Binding {
+	return Binding{value: value}
+}
+
+func NewNamedBinding(name string) Binding {
+	return Binding{name: name}
+}
+
+func (b Binding) Name() string {
+	return b.name
+}
+
+func (b Binding) Value() any {
+	return b.value
+}
+
+func (b Binding) IsNamed() bool {
+	return len(b.name) > 0
+}
+
+type statementBuilder struct {
+	strings.Builder
+	bindings []Binding
+}
+
+func (b *statementBuilder) WriteStatement(statement *Statement) {
+	b.WriteString(statement.sql)
+	b.bindings = append(b.bindings, statement.bindings...)
+}
+
+func (b *statementBuilder) AddArg(arg any) {
+	b.bindings = append(b.bindings, NewValueBinding(arg))
+}
+
+func (b *statementBuilder) AddNamedArg(name string) {
+	b.bindings = append(b.bindings, NewNamedBinding(name))
+}
+
+func (b *statementBuilder) Statement() *Statement {
+	return NewStatement(b.String(), b.bindings)
+}
@@ -1,8 +1,12 @@
 package query
 
 type Subject struct {
-	keywords []string
-	sortable bool
+	keywords          []string
+	sortable          bool
+	projectable       bool
+	assignable        bool
+	insertable        bool
+	aggregateFunction string
 }
 
 func (s *Subject) Keywords() []string {
@@ -13,6 +17,31 @@ func (s *Subject) Sortable() bool {
 	return s.sortable
 }
 
+// Projectable the subject can select specific fields, EX: FindNameAndEmailById
+func (s *Subject) Projectable() bool {
+	return s.projectable
+}
+
+// Assignable the subject is followed by the fields to set, EX: UpdateStatusById
+func (s *Subject) Assignable() bool {
+	return s.assignable
+}
+
+// Insertable the subject inserts the rows of the entity, the method is named by the subject only, EX: Insert
+func (s *Subject) Insertable() bool {
+	return s.insertable
+}
+
+// AggregateFunction the SQL aggregate function of the subject, it is empty if the subject is not an aggregate
+func (s *Subject) AggregateFunction() string {
+	return s.aggregateFunction
+}
+
+// IsAggregate the subject aggregates a target field, EX: SumAmountByUserId
+func (s *Subject) IsAggregate() bool {
+	return len(s.aggregateFunction) > 0
+}
+
 func (s *Subject) Name() string {
 	return s.keywords[0]
 }
@@ -22,14 +51,23 @@ func (s Subject) String() string {
 }
 
 var (
-	SubjectFind   = &Subject{keywords: []string{"Find", "Query", "Get", "Search"}, sortable: true}
+	SubjectFind   = &Subject{keywords: []string{"Find", "Query", "Get", "Search"}, sortable: true, projectable: true}
 	SubjectCount  = &Subject{keywords: []string{"Count"}, sortable: false}
 	SubjectExists = &Subject{keywords: []string{"Exists"}, sortable: false}
 	SubjectDelete = &Subject{keywords: []string{"Delete", "Remove"}, sortable: true}
+	SubjectUpdate = &Subject{keywords: []string{"Update"}, sortable: false, assignable: true}
+	SubjectInsert = &Subject{keywords: []string{"Insert"}, sortable: false, insertable: true}
+	SubjectSave   = &Subject{keywords: []string{"Save"}, sortable: false, insertable: true}
+	SubjectSum    = &Subject{keywords: []string{"Sum"}, sortable: false, aggregateFunction: "SUM"}
+	SubjectAvg    = &Subject{keywords: []string{"Avg", "Average"}, sortable: false, aggregateFunction: "AVG"}
+	SubjectMin    = &Subject{keywords: []string{"Min"}, sortable: false, aggregateFunction: "MIN"}
+	SubjectMax    = &Subject{keywords: []string{"Max"}, sortable: false, aggregateFunction: "MAX"}
 )
 
 var Subjects = []*Subject{
-	SubjectFind, SubjectCount, SubjectExists, SubjectDelete,
+	SubjectFind, SubjectCount, SubjectExists, SubjectDelete, SubjectUpdate,
+	SubjectInsert, SubjectSave,
+	SubjectSum, SubjectAvg, SubjectMin, SubjectMax,
 }
 
 type SubjectModifier struct {
@@ -3,16 +3,24 @@ package query
 import "context"
 
 type Translator interface {
-	Translate(ctx context.Context, query *Query) (string, error)
-	TranslateFind(ctx context.Context, query *Query) (string, error)
-	TranslateCount(ctx context.Context, query *Query) (string, error)
-	TranslateExists(ctx context.Context, query *Query) (string, error)
-	TranslateDelete(ctx context.Context, query *Query) (string, error)
+	Translate(ctx context.Context, query *Query) (*Statement, error)
+	TranslateFind(ctx context.Context, query *Query) (*Statement, error)
+	TranslateCount(ctx context.Context, query *Query) (*Statement, error)
+	TranslateExists(ctx context.Context, query *Query) (*Statement, error)
+	TranslateDelete(ctx context.Context, query *Query) (*Statement, error)
+	TranslateUpdate(ctx context.Context, query *Query) (*Statement, error)
+	TranslateInsert(ctx context.Context, query *Query) (*Statement, error)
+	TranslateAggregate(ctx context.Context, query *Query) (*Statement, error)
 	TranslateTable(ctx context.Context, table Table) (string, error)
-	TranslateFilterGroup(ctx context.Context, group *FilterGroup) (string, error)
-	TranslateFilter(ctx context.Context, filter *Filter) (string, error)
+	TranslateJoins(ctx context.Context, query *Query) (string, error)
+	TranslateProjection(ctx context.Context, projection *Projection) (string, error)
+	TranslateAssignments(ctx context.Context, assignments []*Assignment) (*Statement, error)
+	TranslateGroupBy(ctx context.Context, query *Query) (*Statement, error)
+	TranslateFilterGroup(ctx context.Context, group *FilterGroup) (*Statement, error)
+	TranslateFilter(ctx context.Context, filter *Filter) (*Statement, error)
 	TranslateLogicOperator(ctx context.Context, operator LogicOperator) (string, error)
 	TranslateSorts(ctx context.Context, sorts []*Sort) (string, error)
 	TranslateSort(ctx context.Context, sort *Sort) (string, error)
-	TranslatePager(ctx context.Context, pager Pager) (string, error)
+	TranslatePager(ctx context.Context, pager Pager) (*Statement, error)
+	TranslateKeyset(ctx context.Context, sorts []*Sort, keyset *KeysetRequest) (*Statement, error)
 }
@@ -4,18 +4,63 @@ import (
 	"context"
 	"fmt"
 	"github.com/gomelon/melon/data/engine"
+	"github.com/gomelon/melon/third_party/sqlx"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
 type RDBTranslator struct {
-	engin engine.Engine
+	engin           engine.Engine
+	nestedFieldMode NestedFieldMode
 }
 
-func NewRDBTranslator(engin engine.Engine) *RDBTranslator {
-	return &RDBTranslator{engin: engin}
+// NestedFieldMode decides the column of the nested field, EX: Address_City
+type NestedFieldMode int
+
+const (
+	//NestedFieldFlatten the column address_city of the table
+	NestedFieldFlatten NestedFieldMode = iota
+	//NestedFieldJoin the column city of the table joined as address
+	NestedFieldJoin
+	//NestedFieldJSON the value at the path $.city of the JSON column address
+	NestedFieldJSON
+)
+
+// translateState hold the state shared by the whole translation of one query,
+// it is bound to the context by the top level Translate* methods
+type translateState struct {
+	numBindVar int
+	aggregates map[string]string
+	tableAlias string          //the columns are qualified by the alias if the query has joins
+	joinPaths  map[string]bool //the paths of the joined nested fields
+}
+
+type translateStateKey struct{}
+
+const (
+	pagerOffsetMark = "$$_offset_$$"
+	pagerLimitMark  = "$$_limit_$$"
+)
+
+func NewRDBTranslator(engin engine.Engine, opts ...RDBTranslatorOption) *RDBTranslator {
+	translator := &RDBTranslator{engin: engin}
+	for _, opt := range opts {
+		opt(translator)
+	}
+	return translator
+}
+
+type RDBTranslatorOption func(t *RDBTranslator)
+
+// WithNestedFieldMode default is NestedFieldFlatten
+func WithNestedFieldMode(mode NestedFieldMode) RDBTranslatorOption {
+	return func(t *RDBTranslator) {
+		t.nestedFieldMode = mode
+	}
 }
 
-func (t *RDBTranslator) Translate(ctx context.Context, query *Query) (result string, err error) {
+func (t *RDBTranslator) Translate(ctx context.Context, query *Query) (result *Statement, err error) {
 	switch query.Subject() {
 	case SubjectFind:
 		result, err = t.TranslateFind(ctx, query)
@@ -25,6 +70,12 @@ func (t *RDBTranslator) Translate(ctx context.Context, query *Query) (result str
 		result, err = t.TranslateExists(ctx, query)
 	case SubjectDelete:
 		result, err = t.TranslateDelete(ctx, query)
+	case SubjectUpdate:
+		result, err = t.TranslateUpdate(ctx, query)
+	case SubjectInsert, SubjectSave:
+		result, err = t.TranslateInsert(ctx, query)
+	case SubjectSum, SubjectAvg, SubjectMin, SubjectMax:
+		result, err = t.TranslateAggregate(ctx, query)
 	default:
 		err = fmt.Errorf("translate query fail: unsupported subject [%s]", query.subject.String())
 	}
@@ -41,20 +92,169 @@ func (t *RDBTranslator) TranslateTable(ctx context.Context, table Table) (string
 	return t.engin.Escape(table.Schema()) + "." + t.engin.Escape(table.Name()), nil
 }
 
-func (t *RDBTranslator) TranslateFind(ctx context.Context, query *Query) (result string, err error) {
+// TranslateJoins the joined table is aliased by the path of the nested field,
+// the columns of the rest of the translation are qualified by the table aliases
+func (t *RDBTranslator) TranslateJoins(ctx context.Context, query *Query) (result string, err error) {
+	if len(query.Joins()) == 0 {
+		return
+	}
+	state, ok := ctx.Value(translateStateKey{}).(*translateState)
+	if !ok {
+		state = &translateState{}
+	}
+	if query.Table() == nil {
+		state.tableAlias = t.engin.Escape("$$_table_$$")
+	} else {
+		state.tableAlias = t.engin.Escape(query.Table().Name())
+	}
+	state.joinPaths = make(map[string]bool, len(query.Joins()))
+
+	builder := strings.Builder{}
+	builder.Grow(128)
+	for i, join := range query.Joins() {
+		if join.Table() == nil || len(join.Conditions()) == 0 {
+			err = fmt.Errorf("translate query fail: join [%s] must has the table and the conditions", join.Path())
+			return
+		}
+		parentAlias := state.tableAlias
+		if join.Path().IsNested() {
+			if !state.joinPaths[join.Path().Parent().String()] {
+				err = fmt.Errorf("translate query fail: join [%s] must follow the join of the parent [%s]",
+					join.Path(), join.Path().Parent())
+				return
+			}
+			parentAlias = t.tableAlias(join.Path().Parent())
+		}
+		alias := t.tableAlias(join.Path())
+		if i > 0 {
+			builder.WriteRune(' ')
+		}
+		switch join.JoinType() {
+		case JoinTypeLeft:
+			builder.WriteString("LEFT JOIN ")
+		case JoinTypeInner:
+			builder.WriteString("INNER JOIN ")
+		default:
+			err = fmt.Errorf("translate query fail: unsupported join type [%s]", join.JoinType())
+			return
+		}
+		var tableStr string
+		tableStr, err = t.TranslateTable(ctx, join.Table())
+		if err != nil {
+			return
+		}
+		builder.WriteString(tableStr)
+		builder.WriteRune(' ')
+		builder.WriteString(alias)
+		builder.WriteString(" ON ")
+		for j, condition := range join.Conditions() {
+			if j > 0 {
+				builder.WriteString(" AND ")
+			}
+			builder.WriteString(parentAlias + "." + t.engin.Escape(t.engin.BuildColumn(condition.FieldName())))
+			builder.WriteString(" = ")
+			builder.WriteString(alias + "." + t.engin.Escape(t.engin.BuildColumn(condition.TargetFieldName())))
+		}
+		state.joinPaths[join.Path().String()] = true
+	}
+	result = builder.String()
+	return
+}
+
+func (t *RDBTranslator) translateTableWithJoins(ctx context.Context, query *Query) (result string, err error) {
+	result, err = t.TranslateTable(ctx, query.Table())
+	if err != nil {
+		return
+	}
+	joinsStr, err := t.TranslateJoins(ctx, query)
+	if err != nil {
+		return
+	}
+	if len(joinsStr) > 0 {
+		result += " " + joinsStr
+	}
+	return
+}
+
+// TranslateProjection the fields are not nested, each field is the column built from its name
+func (t *RDBTranslator) TranslateProjection(ctx context.Context, projection *Projection) (result string, err error) {
+	if projection == nil || projection.IsEmpty() {
+		if state, ok := ctx.Value(translateStateKey{}).(*translateState); ok && len(state.tableAlias) > 0 {
+			return state.tableAlias + ".*", nil
+		}
+		return "*", nil
+	}
+	builder := strings.Builder{}
+	builder.Grow(64)
+	for i, field := range projection.Fields() {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		var column string
+		column, err = t.column(ctx, FieldPath{field.FieldName()})
+		if err != nil {
+			return
+		}
+		builder.WriteString(column)
+		if len(field.Alias()) > 0 {
+			builder.WriteString(" AS ")
+			builder.WriteString(t.engin.Escape(field.Alias()))
+		}
+	}
+	result = builder.String()
+	return
+}
+
+func (t *RDBTranslator) TranslateFind(ctx context.Context, query *Query) (result *Statement, err error) {
+	ctx = t.withTranslateState(ctx)
+	pager := query.Pager()
+	tableStr, err := t.translateTableWithJoins(ctx, query)
+	if err != nil {
+		return
+	}
+
+	projectionStr, err := t.TranslateProjection(ctx, query.Projection())
+	if err != nil {
+		return
+	}
+	if query.Projection() == nil && len(query.GroupBy()) > 0 {
+		projectionStr, err = t.groupColumns(ctx, query.GroupBy())
+		if err != nil {
+			return
+		}
+	}
 	var subjectStr string
 	switch query.subjectModifier {
 	case SubjectModifierDistinct:
-		subjectStr = "SELECT DISTINCT * FROM "
+		subjectStr = "SELECT DISTINCT " + projectionStr + " FROM "
+	case SubjectModifierTop:
+		subjectStr = "SELECT " + projectionStr + " FROM "
+		if pager == nil {
+			break
+		}
+		if topStr := t.engin.BuildTop(strconv.Itoa(pager.PageSize())); len(topStr) > 0 {
+			subjectStr = "SELECT " + topStr + " " + projectionStr + " FROM "
+			pager = nil
+		}
 	default:
-		subjectStr = "SELECT * FROM "
+		subjectStr = "SELECT " + projectionStr + " FROM "
 	}
-	tableStr, err := t.TranslateTable(ctx, query.Table())
+
+	where, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
 	if err != nil {
 		return
 	}
 
-	whereStr, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
+	if keyset, ok := query.Pager().(*KeysetRequest); ok {
+		var seek *Statement
+		seek, err = t.TranslateKeyset(ctx, query.Sorts(), keyset)
+		if err != nil {
+			return
+		}
+		where = t.and(where, seek)
+	}
+
+	groupBy, err := t.TranslateGroupBy(ctx, query)
 	if err != nil {
 		return
 	}
@@ -64,67 +264,341 @@ func (t *RDBTranslator) TranslateFind(ctx context.Context, query *Query) (result
 		return
 	}
 
-	pagerStr, err := t.TranslatePager(ctx, query.Pager())
+	pagerStatement, err := t.TranslatePager(ctx, pager)
 	if err != nil {
 		return
 	}
 
-	builder := &strings.Builder{}
-	t.build(builder, subjectStr, tableStr, whereStr, sortsStr, pagerStr)
-	result = builder.String()
+	builder := &statementBuilder{}
+	t.build(builder, subjectStr, tableStr, where, groupBy, sortsStr, pagerStatement)
+	result = builder.Statement()
 	return
 }
 
-func (t *RDBTranslator) TranslateCount(ctx context.Context, query *Query) (result string, err error) {
-	var subjectStr string
-	switch query.subjectModifier {
-	case SubjectModifierDistinct:
-		subjectStr = "SELECT COUNT(DISTINCT *) AS X FROM "
-	default:
-		subjectStr = "SELECT COUNT(*) AS X FROM "
+// TranslateCount the distinct rows of the projection are counted by the subquery of the distinct Find,
+// the sorts and pager are ignored
+func (t *RDBTranslator) TranslateCount(ctx context.Context, query *Query) (result *Statement, err error) {
+	ctx = t.withTranslateState(ctx)
+	if query.subjectModifier == SubjectModifierDistinct {
+		return t.translateCountDistinct(ctx, query)
 	}
+	tableStr, err := t.translateTableWithJoins(ctx, query)
+	if err != nil {
+		return
+	}
+	return t.translateAggregate(ctx, query, tableStr, "COUNT(*)")
+}
+
+func (t *RDBTranslator) translateCountDistinct(ctx context.Context, query *Query) (result *Statement, err error) {
+	if len(query.GroupBy()) > 0 {
+		err = fmt.Errorf("translate query fail: count distinct can not be grouped")
+		return
+	}
+	findStatement, err := t.TranslateFind(ctx, query.With(WithSubject(SubjectFind), WithSorts(nil), WithPager(nil)))
+	if err != nil {
+		return
+	}
+	builder := &statementBuilder{}
+	builder.WriteString("SELECT COUNT(*) AS X FROM (")
+	builder.WriteStatement(findStatement)
+	builder.WriteString(") T")
+	result = builder.Statement()
+	return
+}
+
+func (t *RDBTranslator) TranslateExists(ctx context.Context, query *Query) (result *Statement, err error) {
+	ctx = t.withTranslateState(ctx)
+	subjectStr := "SELECT 1 AS X FROM "
+	tableStr, err := t.translateTableWithJoins(ctx, query)
+	if err != nil {
+		return
+	}
+
+	where, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
+	if err != nil {
+		return
+	}
+
+	groupBy, err := t.TranslateGroupBy(ctx, query)
+	if err != nil {
+		return
+	}
+
+	builder := &statementBuilder{}
+	t.build(builder, subjectStr, tableStr, where, groupBy, "", NewStatement(t.engin.BuildLimit("0", "1"), nil))
+	result = builder.Statement()
+	return
+}
+
+// TranslateAggregate SUM of the empty set is 0, AVG MIN and MAX of the empty set is NULL
+func (t *RDBTranslator) TranslateAggregate(ctx context.Context, query *Query) (result *Statement, err error) {
+	ctx = t.withTranslateState(ctx)
+	subject := query.Subject()
+	if !subject.IsAggregate() {
+		err = fmt.Errorf("translate query fail: subject [%s] is not an aggregate", subject.String())
+		return
+	}
+	if len(query.AggregateField()) == 0 {
+		err = fmt.Errorf("translate query fail: aggregate subject [%s] must has a target field", subject.String())
+		return
+	}
+	tableStr, err := t.translateTableWithJoins(ctx, query)
+	if err != nil {
+		return
+	}
+	column, err := t.column(ctx, NewFieldPath(query.AggregateField()))
+	if err != nil {
+		return
+	}
+	aggregateStr := subject.AggregateFunction() + "(" + column + ")"
+	if subject == SubjectSum {
+		aggregateStr = "COALESCE(" + aggregateStr + ", 0)"
+	}
+	return t.translateAggregate(ctx, query, tableStr, aggregateStr)
+}
+
+// translateAggregate the grouping columns are selected before the aggregate value if the query is grouped,
+// the having and sorts can refer to the aggregate value by the name of the subject
+func (t *RDBTranslator) translateAggregate(ctx context.Context, query *Query, tableStr string, aggregateStr string,
+) (result *Statement, err error) {
+
+	subjectStr := "SELECT " + aggregateStr + " AS X FROM "
+	if len(query.GroupBy()) > 0 {
+		var groupStr string
+		groupStr, err = t.groupColumns(ctx, query.GroupBy())
+		if err != nil {
+			return
+		}
+		subjectStr = "SELECT " + groupStr + ", " + aggregateStr + " AS X FROM "
+	}
+
+	where, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
+	if err != nil {
+		return
+	}
+
+	t.bindAggregate(ctx, query.Subject().Name(), aggregateStr)
+	groupBy, err := t.TranslateGroupBy(ctx, query)
+	if err != nil {
+		return
+	}
+
+	var sortsStr string
+	if len(query.GroupBy()) > 0 {
+		sortsStr, err = t.TranslateSorts(ctx, query.Sorts())
+		if err != nil {
+			return
+		}
+	}
+
+	builder := &statementBuilder{}
+	t.build(builder, subjectStr, tableStr, where, groupBy, sortsStr, nil)
+	result = builder.Statement()
+	return
+}
+
+func (t *RDBTranslator) TranslateDelete(ctx context.Context, query *Query) (result *Statement, err error) {
+	ctx = t.withTranslateState(ctx)
+	subjectStr := "DELETE FROM "
 	tableStr, err := t.TranslateTable(ctx, query.Table())
 	if err != nil {
 		return
 	}
 
-	whereStr, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
+	if len(query.GroupBy()) > 0 || query.Having() != nil {
+		err = fmt.Errorf("translate query fail: subject [%s] can not be grouped", query.Subject().String())
+		return
+	}
+	if len(query.Joins()) > 0 {
+		err = fmt.Errorf("translate query fail: subject [%s] can not be joined", query.Subject().String())
+		return
+	}
+
+	rowID := t.engin.BuildRowID()
+	if len(rowID) > 0 && query.Pager() != nil {
+		return t.translateDeleteBySubquery(ctx, query, tableStr, rowID)
+	}
+	if query.Pager() != nil && len(t.engin.BuildTop("1")) > 0 {
+		return t.translateDeleteByCTE(ctx, query, tableStr)
+	}
+
+	where, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
 	if err != nil {
 		return
 	}
 
-	builder := &strings.Builder{}
-	t.build(builder, subjectStr, tableStr, whereStr, "", "")
-	result = builder.String()
+	var sortsStr string
+	if len(rowID) == 0 {
+		sortsStr, err = t.TranslateSorts(ctx, query.Sorts())
+		if err != nil {
+			return
+		}
+	}
+
+	pagerStatement, err := t.TranslatePager(ctx, query.Pager())
+	if err != nil {
+		return
+	}
+
+	builder := &statementBuilder{}
+	t.build(builder, subjectStr, tableStr, where, nil, sortsStr, pagerStatement)
+	result = builder.Statement()
 	return
 }
 
-func (t *RDBTranslator) TranslateExists(ctx context.Context, query *Query) (result string, err error) {
-	subjectStr := "SELECT 1 AS X FROM "
+// TranslateUpdate the args of the set clause precede the args of the filters
+func (t *RDBTranslator) TranslateUpdate(ctx context.Context, query *Query) (result *Statement, err error) {
+	ctx = t.withTranslateState(ctx)
+	if len(query.GroupBy()) > 0 || query.Having() != nil {
+		err = fmt.Errorf("translate query fail: subject [%s] can not be grouped", query.Subject().String())
+		return
+	}
+	if len(query.Joins()) > 0 {
+		err = fmt.Errorf("translate query fail: subject [%s] can not be joined", query.Subject().String())
+		return
+	}
 	tableStr, err := t.TranslateTable(ctx, query.Table())
 	if err != nil {
 		return
 	}
 
-	whereStr, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
+	assignments, err := t.TranslateAssignments(ctx, query.Assignments())
 	if err != nil {
 		return
 	}
 
-	builder := &strings.Builder{}
-	t.build(builder, subjectStr, tableStr, whereStr, "", t.engin.BuildLimit("0", "1"))
-	result = builder.String()
+	where, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
+	if err != nil {
+		return
+	}
+
+	builder := &statementBuilder{}
+	builder.WriteString("UPDATE ")
+	builder.WriteString(tableStr)
+	builder.WriteString(" SET ")
+	builder.WriteStatement(assignments)
+	t.build(builder, "", "", where, nil, "", nil)
+	result = builder.Statement()
 	return
 }
 
-func (t *RDBTranslator) TranslateDelete(ctx context.Context, query *Query) (result string, err error) {
-	subjectStr := "DELETE FROM "
+// TranslateInsert the values are bound row by row, Save updates the rows conflicting on the conflict fields
+// by the upsert clause of the engine, the other fields are updated
+func (t *RDBTranslator) TranslateInsert(ctx context.Context, query *Query) (result *Statement, err error) {
+	ctx = t.withTranslateState(ctx)
+	if len(query.Joins()) > 0 {
+		err = fmt.Errorf("translate query fail: subject [%s] can not be joined", query.Subject().String())
+		return
+	}
+	insertion := query.Insertion()
+	if insertion == nil || len(insertion.FieldNames()) == 0 {
+		err = fmt.Errorf("translate query fail: subject [%s] must has the fields to insert", query.Subject().String())
+		return
+	}
 	tableStr, err := t.TranslateTable(ctx, query.Table())
 	if err != nil {
 		return
 	}
 
-	whereStr, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
+	columns := make([]string, 0, len(insertion.FieldNames()))
+	for _, fieldName := range insertion.FieldNames() {
+		columns = append(columns, t.engin.Escape(t.engin.BuildColumn(fieldName)))
+	}
+
+	builder := &statementBuilder{}
+	builder.Grow(256)
+	builder.WriteString("INSERT INTO ")
+	builder.WriteString(tableStr)
+	builder.WriteString(" (")
+	builder.WriteString(strings.Join(columns, ", "))
+	builder.WriteString(") VALUES ")
+	rows := insertion.Rows()
+	for i := 0; i < insertion.NumRow(); i++ {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteRune('(')
+		for j := range columns {
+			if j > 0 {
+				builder.WriteString(", ")
+			}
+			var value any
+			if i < len(rows) {
+				value = rows[i][j]
+			}
+			builder.AddArg(value)
+			builder.WriteString(t.bindVar(ctx))
+		}
+		builder.WriteRune(')')
+	}
+
+	if query.Subject() == SubjectSave {
+		var upsertStr string
+		upsertStr, err = t.translateUpsert(insertion, columns)
+		if err != nil {
+			return
+		}
+		builder.WriteRune(' ')
+		builder.WriteString(upsertStr)
+	}
+	result = builder.Statement()
+	return
+}
+
+// translateUpsert the conflict fields are compared by their columns, EX: Id is the same as the field tagged by id
+func (t *RDBTranslator) translateUpsert(insertion *Insertion, columns []string) (result string, err error) {
+	isConflictColumn := make(map[string]bool, len(insertion.ConflictFields()))
+	conflictColumns := make([]string, 0, len(insertion.ConflictFields()))
+	for _, fieldName := range insertion.ConflictFields() {
+		column := t.engin.Escape(t.engin.BuildColumn(fieldName))
+		isConflictColumn[column] = true
+		conflictColumns = append(conflictColumns, column)
+	}
+	updateColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if !isConflictColumn[column] {
+			updateColumns = append(updateColumns, column)
+		}
+	}
+	result, err = t.engin.BuildUpsert(conflictColumns, updateColumns)
+	if err != nil {
+		err = fmt.Errorf("translate query fail: %w", err)
+	}
+	return
+}
+
+func (t *RDBTranslator) TranslateAssignments(ctx context.Context, assignments []*Assignment,
+) (result *Statement, err error) {
+
+	if len(assignments) == 0 {
+		err = fmt.Errorf("translate query fail: the set clause must has at least one field")
+		return
+	}
+	builder := &statementBuilder{}
+	builder.Grow(64)
+	for i, assignment := range assignments {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(t.engin.Escape(t.engin.BuildColumn(assignment.FieldName())))
+		builder.WriteString(" = ")
+		if len(assignment.NamedArg()) > 0 {
+			builder.AddNamedArg(assignment.NamedArg())
+		} else {
+			builder.AddArg(assignment.Value())
+		}
+		builder.WriteString(t.bindVar(ctx))
+	}
+	result = builder.Statement()
+	return
+}
+
+// translateDeleteBySubquery the engine can not limit the rows of DELETE directly,
+// so select the row ids to delete by a subquery
+func (t *RDBTranslator) translateDeleteBySubquery(ctx context.Context, query *Query, tableStr string, rowID string,
+) (result *Statement, err error) {
+
+	where, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
 	if err != nil {
 		return
 	}
@@ -134,26 +608,98 @@ func (t *RDBTranslator) TranslateDelete(ctx context.Context, query *Query) (resu
 		return
 	}
 
-	pagerStr, err := t.TranslatePager(ctx, query.Pager())
+	pagerStatement, err := t.TranslatePager(ctx, query.Pager())
 	if err != nil {
 		return
 	}
 
-	builder := &strings.Builder{}
-	t.build(builder, subjectStr, tableStr, whereStr, sortsStr, pagerStr)
-	result = builder.String()
+	builder := &statementBuilder{}
+	builder.WriteString("DELETE FROM ")
+	builder.WriteString(tableStr)
+	builder.WriteString(" WHERE ")
+	builder.WriteString(rowID)
+	builder.WriteString(" IN (")
+	t.build(builder, "SELECT "+rowID+" FROM ", tableStr, where, nil, sortsStr, pagerStatement)
+	builder.WriteRune(')')
+	result = builder.Statement()
 	return
 }
 
-func (t *RDBTranslator) TranslateFilterGroup(ctx context.Context, fg *FilterGroup) (result string, err error) {
-	operator, err := t.TranslateLogicOperator(ctx, fg.logicOperator)
+// translateDeleteByCTE the engine limits rows by TOP and its DELETE has no ORDER BY,
+// so select the rows to delete by a common table expression and delete through it
+func (t *RDBTranslator) translateDeleteByCTE(ctx context.Context, query *Query, tableStr string,
+) (result *Statement, err error) {
+
+	pager := query.Pager()
+	subjectStr := "SELECT * FROM "
+	if query.subjectModifier == SubjectModifierTop {
+		subjectStr = "SELECT " + t.engin.BuildTop(strconv.Itoa(pager.PageSize())) + " * FROM "
+		pager = nil
+	}
+
+	where, err := t.TranslateFilterGroup(ctx, query.FilterGroup())
+	if err != nil {
+		return
+	}
+
+	sortsStr, err := t.TranslateSorts(ctx, query.Sorts())
+	if err != nil {
+		return
+	}
+
+	pagerStatement, err := t.TranslatePager(ctx, pager)
+	if err != nil {
+		return
+	}
+
+	builder := &statementBuilder{}
+	builder.WriteString("WITH X AS (")
+	t.build(builder, subjectStr, tableStr, where, nil, sortsStr, pagerStatement)
+	builder.WriteString(") DELETE FROM X")
+	result = builder.Statement()
+	return
+}
+
+func (t *RDBTranslator) TranslateGroupBy(ctx context.Context, query *Query) (result *Statement, err error) {
+	result = &Statement{}
+	if len(query.GroupBy()) == 0 && query.Having() == nil {
+		return
+	}
+	builder := &statementBuilder{}
+	if len(query.GroupBy()) > 0 {
+		var groupStr string
+		groupStr, err = t.groupColumns(ctx, query.GroupBy())
+		if err != nil {
+			return
+		}
+		builder.WriteString("GROUP BY ")
+		builder.WriteString(groupStr)
+	}
+	having, err := t.TranslateFilterGroup(ctx, query.Having())
 	if err != nil {
 		return
 	}
+	if !having.IsEmpty() {
+		if builder.Len() > 0 {
+			builder.WriteRune(' ')
+		}
+		builder.WriteString("HAVING ")
+		builder.WriteStatement(having)
+	}
+	result = builder.Statement()
+	return
+}
+
+func (t *RDBTranslator) TranslateFilterGroup(ctx context.Context, fg *FilterGroup) (result *Statement, err error) {
+	result = &Statement{}
 	if fg == nil || fg.IsEmpty() {
 		return
 	}
-	builder := strings.Builder{}
+	operator, err := t.TranslateLogicOperator(ctx, fg.logicOperator)
+	if err != nil {
+		return
+	}
+	builder := &statementBuilder{}
 	builder.Grow(256)
 
 	isMultiple := len(fg.groups) > 1 || len(fg.filters) > 1
@@ -161,7 +707,7 @@ func (t *RDBTranslator) TranslateFilterGroup(ctx context.Context, fg *FilterGrou
 		builder.WriteRune('(')
 	}
 
-	var elementResult string
+	var elementResult *Statement
 	for i, group := range fg.groups {
 		if i > 0 {
 			builder.WriteRune(' ')
@@ -172,7 +718,7 @@ func (t *RDBTranslator) TranslateFilterGroup(ctx context.Context, fg *FilterGrou
 		if err != nil {
 			return
 		}
-		builder.WriteString(elementResult)
+		builder.WriteStatement(elementResult)
 	}
 	for i, filter := range fg.filters {
 		if i > 0 {
@@ -184,66 +730,102 @@ func (t *RDBTranslator) TranslateFilterGroup(ctx context.Context, fg *FilterGrou
 		if err != nil {
 			return
 		}
-		builder.WriteString(elementResult)
+		builder.WriteStatement(elementResult)
 	}
 
 	if isMultiple {
 		builder.WriteRune(')')
 	}
 
-	result = builder.String()
+	result = builder.Statement()
 	return
 }
 
-func (t *RDBTranslator) TranslateFilter(ctx context.Context, f *Filter) (result string, err error) {
+func (t *RDBTranslator) TranslateFilter(ctx context.Context, f *Filter) (result *Statement, err error) {
 	e := t.engin
-	column := e.Escape(e.BuildColumn(f.FieldName()))
+	rawColumn, err := t.column(ctx, f.FieldPath())
+	if err != nil {
+		return
+	}
+	builder := &statementBuilder{}
+	//IgnoreCase lowers both sides of the comparison
+	column := t.lowerIfIgnoreCase(f, rawColumn)
+	arg := func(index int) string {
+		return t.lowerIfIgnoreCase(f, t.bindArg(ctx, builder, f, index))
+	}
 	switch f.Predicate() {
 	case PredicateIs:
-		result = fmt.Sprintf("(%s = %s)", column, t.namedArgOrValue(f, 0))
+		builder.WriteString(fmt.Sprintf("(%s = %s)", column, arg(0)))
 	case PredicateIsNot:
-		result = fmt.Sprintf("(%s != %s)", column, t.namedArgOrValue(f, 0))
+		builder.WriteString(fmt.Sprintf("(%s != %s)", column, arg(0)))
 	case PredicateGT:
-		result = fmt.Sprintf("(%s > %s)", column, t.namedArgOrValue(f, 0))
+		builder.WriteString(fmt.Sprintf("(%s > %s)", column, arg(0)))
 	case PredicateLT:
-		result = fmt.Sprintf("(%s < %s)", column, t.namedArgOrValue(f, 0))
+		builder.WriteString(fmt.Sprintf("(%s < %s)", column, arg(0)))
 	case PredicateGTE:
-		result = fmt.Sprintf("(%s >= %s)", column, t.namedArgOrValue(f, 0))
+		builder.WriteString(fmt.Sprintf("(%s >= %s)", column, arg(0)))
 	case PredicateLTE:
-		result = fmt.Sprintf("(%s <= %s)", column, t.namedArgOrValue(f, 0))
+		builder.WriteString(fmt.Sprintf("(%s <= %s)", column, arg(0)))
 	case PredicateBetween:
-		result = fmt.Sprintf("(%s >= %s AND %s <= %s)",
-			column, column, t.namedArgOrValue(f, 0), t.namedArgOrValue(f, 1))
+		builder.WriteString(fmt.Sprintf("(%s >= %s AND %s <= %s)", column, arg(0), column, arg(1)))
 	case PredicateIn:
-		result = fmt.Sprintf("(%s in (%s))", column, t.namedArgOrValue(f, 0))
+		if placeholders := t.bindSliceArg(ctx, builder, f); len(placeholders) > 0 {
+			builder.WriteString(fmt.Sprintf("(%s in (%s))", column, placeholders))
+		} else {
+			builder.WriteString("(1 = 0)")
+		}
 	case PredicateNotIn:
-		result = fmt.Sprintf("(%s NOT IN (%s))", column, t.namedArgOrValue(f, 0))
+		if placeholders := t.bindSliceArg(ctx, builder, f); len(placeholders) > 0 {
+			builder.WriteString(fmt.Sprintf("(%s NOT IN (%s))", column, placeholders))
+		} else {
+			builder.WriteString("(1 = 1)")
+		}
 	case PredicateContains:
-		result = fmt.Sprintf("(%s %s)", column, e.BuildContains(t.namedArgOrValue(f, 0)))
+		builder.WriteString(fmt.Sprintf("(%s)",
+			e.BuildContains(rawColumn, t.bindArg(ctx, builder, f, 0), f.IgnoreCase())))
 	case PredicateStartsWith:
-		result = fmt.Sprintf("(%s %s)", column, e.BuildStartsWith(t.namedArgOrValue(f, 0)))
+		builder.WriteString(fmt.Sprintf("(%s)",
+			e.BuildStartsWith(rawColumn, t.bindArg(ctx, builder, f, 0), f.IgnoreCase())))
 	case PredicateEndsWith:
-		result = fmt.Sprintf("(%s %s)", column, e.BuildEndsWith(t.namedArgOrValue(f, 0)))
+		builder.WriteString(fmt.Sprintf("(%s)",
+			e.BuildEndsWith(rawColumn, t.bindArg(ctx, builder, f, 0), f.IgnoreCase())))
 	case PredicateIsNull:
-		result = fmt.Sprintf("(%s IS NULL)", column)
+		builder.WriteString(fmt.Sprintf("(%s IS NULL)", rawColumn))
 	case PredicateIsNotNull:
-		result = fmt.Sprintf("(%s IS NOT NULL)", column)
+		builder.WriteString(fmt.Sprintf("(%s IS NOT NULL)", rawColumn))
 	case PredicateIsEmpty:
-		//TODO wait implement
-		panic("wait implement")
+		var condition string
+		condition, err = e.BuildIsEmpty(rawColumn, f.Collection())
+		if err != nil {
+			err = fmt.Errorf("translate query fail: %w", err)
+			return
+		}
+		builder.WriteString(fmt.Sprintf("(%s)", condition))
 	case PredicateIsNotEmpty:
-		//TODO wait implement
-		panic("wait implement")
+		var condition string
+		condition, err = e.BuildIsNotEmpty(rawColumn, f.Collection())
+		if err != nil {
+			err = fmt.Errorf("translate query fail: %w", err)
+			return
+		}
+		builder.WriteString(fmt.Sprintf("(%s)", condition))
 	case PredicateIsFalse:
-		result = fmt.Sprintf("(!%s)", column)
+		builder.WriteString(fmt.Sprintf("(%s)", e.BuildIsFalse(rawColumn)))
 	case PredicateIsTrue:
-		result = fmt.Sprintf("(%s)", column)
+		builder.WriteString(fmt.Sprintf("(%s)", e.BuildIsTrue(rawColumn)))
 	case PredicateMatches:
-		//TODO wait implement
-		panic("wait implement")
+		var condition string
+		condition, err = e.BuildMatches(rawColumn, t.bindArg(ctx, builder, f, 0), f.IgnoreCase())
+		if err != nil {
+			err = fmt.Errorf("translate query fail: %w", err)
+			return
+		}
+		builder.WriteString(fmt.Sprintf("(%s)", condition))
 	default:
 		err = fmt.Errorf("translate query fail: unsupoorted predicate [%s]", f.Predicate().String())
+		return
 	}
+	result = builder.Statement()
 	return
 }
 
@@ -282,40 +864,295 @@ func (t *RDBTranslator) TranslateSorts(ctx context.Context, sorts []*Sort) (resu
 }
 
 func (t *RDBTranslator) TranslateSort(ctx context.Context, sort *Sort) (result string, err error) {
-	column := t.engin.Escape(t.engin.BuildColumn(sort.FieldName()))
+	column, err := t.column(ctx, sort.FieldPath())
+	if err != nil {
+		return
+	}
 	return fmt.Sprintf("%s %s", column, strings.ToUpper(string(sort.Direction()))), nil
 }
 
-func (t *RDBTranslator) TranslatePager(ctx context.Context, pager Pager) (result string, err error) {
+func (t *RDBTranslator) TranslatePager(ctx context.Context, pager Pager) (result *Statement, err error) {
+	result = &Statement{}
 	if pager == nil {
 		return
 	}
-	return t.engin.BuildLimit("?", "?"), nil
+	//the engine decides the order of offset and limit, so bind them in the order they appear
+	limitStr := t.engin.BuildLimit(pagerOffsetMark, pagerLimitMark)
+	builder := &statementBuilder{}
+	if strings.Index(limitStr, pagerOffsetMark) < strings.Index(limitStr, pagerLimitMark) {
+		limitStr = strings.Replace(limitStr, pagerOffsetMark, t.bindVar(ctx), 1)
+		limitStr = strings.Replace(limitStr, pagerLimitMark, t.bindVar(ctx), 1)
+		builder.AddArg(pager.Offset())
+		builder.AddArg(pager.PageSize())
+	} else {
+		limitStr = strings.Replace(limitStr, pagerLimitMark, t.bindVar(ctx), 1)
+		limitStr = strings.Replace(limitStr, pagerOffsetMark, t.bindVar(ctx), 1)
+		builder.AddArg(pager.PageSize())
+		builder.AddArg(pager.Offset())
+	}
+	builder.WriteString(limitStr)
+	result = builder.Statement()
+	return
+}
+
+// TranslateKeyset returns the predicate seeking the rows after the keys of the keyset pager in the order of the sorts,
+// EX: (a, b) > (?, ?), it is expanded to (a > ? OR (a = ? AND b > ?))
+// if the directions of the sorts are mixed or the engine does not support row value
+func (t *RDBTranslator) TranslateKeyset(ctx context.Context, sorts []*Sort, keyset *KeysetRequest) (
+	result *Statement, err error) {
+
+	result = &Statement{}
+	if len(sorts) == 0 {
+		err = fmt.Errorf("translate query fail: keyset pager requires sorts")
+		return
+	}
+	keys := keyset.Keys()
+	if len(keys) == 0 {
+		return
+	}
+	if len(keys) != len(sorts) {
+		err = fmt.Errorf("translate query fail: keyset pager expected %d keys, but actual %d keys",
+			len(sorts), len(keys))
+		return
+	}
+
+	columns := make([]string, 0, len(sorts))
+	operators := make([]string, 0, len(sorts))
+	sameDirection := true
+	for _, sort := range sorts {
+		var column string
+		column, err = t.column(ctx, sort.FieldPath())
+		if err != nil {
+			return
+		}
+		columns = append(columns, column)
+		if sort.Direction() == DirectionDesc {
+			operators = append(operators, "<")
+		} else {
+			operators = append(operators, ">")
+		}
+		sameDirection = sameDirection && sort.Direction() == sorts[0].Direction()
+	}
+
+	builder := &statementBuilder{}
+	builder.Grow(64 * len(sorts))
+	if len(sorts) == 1 {
+		builder.AddArg(keys[0])
+		builder.WriteString(fmt.Sprintf("(%s %s %s)", columns[0], operators[0], t.bindVar(ctx)))
+		result = builder.Statement()
+		return
+	}
+	if sameDirection && t.engin.SupportRowValue() {
+		placeholders := make([]string, 0, len(keys))
+		for _, key := range keys {
+			builder.AddArg(key)
+			placeholders = append(placeholders, t.bindVar(ctx))
+		}
+		builder.WriteString(fmt.Sprintf("((%s) %s (%s))",
+			strings.Join(columns, ", "), operators[0], strings.Join(placeholders, ", ")))
+		result = builder.Statement()
+		return
+	}
+
+	builder.WriteRune('(')
+	for i := range sorts {
+		if i > 0 {
+			builder.WriteString(" OR ")
+			builder.WriteRune('(')
+		}
+		for j := 0; j < i; j++ {
+			builder.AddArg(keys[j])
+			builder.WriteString(fmt.Sprintf("%s = %s AND ", columns[j], t.bindVar(ctx)))
+		}
+		builder.AddArg(keys[i])
+		builder.WriteString(fmt.Sprintf("%s %s %s", columns[i], operators[i], t.bindVar(ctx)))
+		if i > 0 {
+			builder.WriteRune(')')
+		}
+	}
+	builder.WriteRune(')')
+	result = builder.Statement()
+	return
 }
 
-func (t *RDBTranslator) build(builder *strings.Builder, subjectStr string, tableStr string,
-	whereStr string, sortsStr string, pagerStr string) {
+// and joins the statements with AND, the empty statement is skipped
+func (t *RDBTranslator) and(left *Statement, right *Statement) *Statement {
+	if right == nil || right.IsEmpty() {
+		return left
+	}
+	if left == nil || left.IsEmpty() {
+		return right
+	}
+	builder := &statementBuilder{}
+	builder.Grow(len(left.SQL()) + len(right.SQL()) + 5)
+	builder.WriteStatement(left)
+	builder.WriteString(" AND ")
+	builder.WriteStatement(right)
+	return builder.Statement()
+}
 
-	builder.Grow(len(subjectStr) + len(tableStr) + len(whereStr) + len(sortsStr) + len(pagerStr) + 8)
+func (t *RDBTranslator) build(builder *statementBuilder, subjectStr string, tableStr string,
+	where *Statement, groupBy *Statement, sortsStr string, pager *Statement) {
+
+	builder.Grow(len(subjectStr) + len(tableStr) + len(sortsStr) + 256)
 	builder.WriteString(subjectStr)
 	builder.WriteString(tableStr)
-	if len(whereStr) > 0 {
+	if where != nil && !where.IsEmpty() {
 		builder.WriteString(" WHERE ")
-		builder.WriteString(whereStr)
+		builder.WriteStatement(where)
+	}
+	if groupBy != nil && !groupBy.IsEmpty() {
+		builder.WriteRune(' ')
+		builder.WriteStatement(groupBy)
+	}
+	hasPager := pager != nil && !pager.IsEmpty()
+	if len(sortsStr) == 0 && hasPager {
+		sortsStr = t.engin.BuildDefaultOrderBy()
 	}
 	if len(sortsStr) > 0 {
 		builder.WriteRune(' ')
 		builder.WriteString(sortsStr)
 	}
-	if len(pagerStr) > 0 {
+	if hasPager {
 		builder.WriteRune(' ')
-		builder.WriteString(pagerStr)
+		builder.WriteStatement(pager)
+	}
+}
+
+func (t *RDBTranslator) groupColumns(ctx context.Context, fieldNames []string) (result string, err error) {
+	columns := make([]string, 0, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		var column string
+		column, err = t.column(ctx, NewFieldPath(fieldName))
+		if err != nil {
+			return
+		}
+		columns = append(columns, column)
+	}
+	result = strings.Join(columns, ", ")
+	return
+}
+
+// column returns the escaped column of the field, or the aggregate expression if the field refers to it,
+// the nested field is the column of the joined table if its parent path is joined, else decided by NestedFieldMode,
+// the parent path must be joined in NestedFieldJoin
+func (t *RDBTranslator) column(ctx context.Context, path FieldPath) (result string, err error) {
+	state, _ := ctx.Value(translateStateKey{}).(*translateState)
+	if state == nil {
+		state = &translateState{}
+	}
+	if aggregateStr, ok := state.aggregates[path.String()]; ok {
+		return aggregateStr, nil
+	}
+	if !path.IsNested() {
+		return t.qualify(state, t.engin.Escape(t.engin.BuildColumn(path.String()))), nil
+	}
+	if state.joinPaths[path.Parent().String()] {
+		return t.tableAlias(path.Parent()) + "." + t.engin.Escape(t.engin.BuildColumn(path.Leaf())), nil
+	}
+	switch t.nestedFieldMode {
+	case NestedFieldJoin:
+		err = fmt.Errorf("translate query fail: nested field [%s] must has the join of [%s]", path, path.Parent())
+	case NestedFieldJSON:
+		keys := make([]string, 0, len(path)-1)
+		for _, key := range path[1:] {
+			keys = append(keys, t.engin.BuildColumn(key))
+		}
+		result = t.engin.BuildJSONPath(t.qualify(state, t.engin.Escape(t.engin.BuildColumn(path.Root()))), keys)
+	default:
+		result = t.qualify(state, t.engin.Escape(t.engin.BuildColumn(path.String())))
+	}
+	return
+}
+
+func (t *RDBTranslator) qualify(state *translateState, column string) string {
+	if len(state.tableAlias) == 0 {
+		return column
+	}
+	return state.tableAlias + "." + column
+}
+
+func (t *RDBTranslator) tableAlias(path FieldPath) string {
+	return t.engin.Escape(t.engin.BuildColumn(path.String()))
+}
+
+// bindAggregate makes the field named by name refer to the aggregate expression in the rest of the translation,
+// it should be called after the WHERE is translated, because the aggregate can not be used in the WHERE
+func (t *RDBTranslator) bindAggregate(ctx context.Context, name string, aggregateStr string) {
+	state, ok := ctx.Value(translateStateKey{}).(*translateState)
+	if !ok {
+		return
+	}
+	if state.aggregates == nil {
+		state.aggregates = make(map[string]string, 1)
 	}
+	state.aggregates[name] = aggregateStr
 }
 
-func (t *RDBTranslator) namedArgOrValue(f *Filter, index int) string {
+func (t *RDBTranslator) lowerIfIgnoreCase(f *Filter, str string) string {
+	if !f.IgnoreCase() {
+		return str
+	}
+	return "LOWER(" + str + ")"
+}
+
+// bindArg returns the placeholder of the index-th value of the filter, and collects the value into the builder.
+// The named args are numbered as the positional ones, they are bound by Statement.BindNamedArgs
+func (t *RDBTranslator) bindArg(ctx context.Context, builder *statementBuilder, f *Filter, index int) string {
 	if f.NamedArgs() != nil {
-		return ":" + f.NamedArgs()[index]
+		builder.AddNamedArg(f.NamedArgs()[index])
+		return t.bindVar(ctx)
+	}
+	var value any
+	if index < len(f.Values()) {
+		value = f.Values()[index]
+	}
+	builder.AddArg(value)
+	return t.bindVar(ctx)
+}
+
+// bindSliceArg the value of In and NotIn is flattened, each element has its own placeholder,
+// returns empty if the value is an empty slice
+func (t *RDBTranslator) bindSliceArg(ctx context.Context, builder *statementBuilder, f *Filter) string {
+	if f.NamedArgs() != nil || len(f.Values()) == 0 {
+		return t.lowerIfIgnoreCase(f, t.bindArg(ctx, builder, f, 0))
+	}
+	value := reflect.ValueOf(f.Values()[0])
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array ||
+		value.Type().Elem().Kind() == reflect.Uint8 {
+		return t.lowerIfIgnoreCase(f, t.bindArg(ctx, builder, f, 0))
+	}
+	placeholders := make([]string, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		builder.AddArg(value.Index(i).Interface())
+		placeholders = append(placeholders, t.lowerIfIgnoreCase(f, t.bindVar(ctx)))
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// bindVar returns the placeholder of the next bound argument,
+// placeholders are numbered in the order they appear in the translated SQL
+func (t *RDBTranslator) bindVar(ctx context.Context) string {
+	state, ok := ctx.Value(translateStateKey{}).(*translateState)
+	if !ok {
+		state = &translateState{}
+	}
+	state.numBindVar++
+	switch t.engin.BindType() {
+	case sqlx.DOLLAR:
+		return "$" + strconv.Itoa(state.numBindVar)
+	case sqlx.NAMED:
+		return ":arg" + strconv.Itoa(state.numBindVar)
+	case sqlx.AT:
+		return "@p" + strconv.Itoa(state.numBindVar)
+	default:
+		return "?"
+	}
+}
+
+func (t *RDBTranslator) withTranslateState(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(translateStateKey{}).(*translateState); ok {
+		return ctx
 	}
-	return "?"
+	return context.WithValue(ctx, translateStateKey{}, &translateState{})
 }
@@ -3,10 +3,26 @@ package query
 import (
 	"context"
 	"github.com/gomelon/melon/data/engine"
+	"reflect"
 	"testing"
 )
 
 func TestRDBTranslator_TranslateFind(t1 *testing.T) {
+	type UserBase struct {
+		Id int64
+	}
+	type UserBrief struct {
+		UserBase
+		Name     string
+		Email    string `db:"mail_address"`
+		password string
+		Extra    string `db:"-"`
+	}
+	briefProjection, err := NewProjectionFromStruct(&UserBrief{})
+	if err != nil {
+		t1.Fatal(err)
+	}
+
 	tests := []struct {
 		name        string
 		query       *Query
@@ -33,9 +49,19 @@ func TestRDBTranslator_TranslateFind(t1 *testing.T) {
 				),
 				WithPager(NewPageRequest(1, 10, false)),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "SELECT * FROM `user` WHERE (`id` = ?) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"MySQL":      "SELECT * FROM `user` WHERE (`id` = ?) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"PostgreSQL": `SELECT * FROM "user" WHERE ("id" = $1) ORDER BY "firstname" ASC LIMIT $2 OFFSET $3`,
+				"SQLite":     `SELECT * FROM "user" WHERE ("id" = ?) ORDER BY "firstname" ASC LIMIT ? OFFSET ?`,
+				"SQLServer":  `SELECT * FROM [user] WHERE ([id] = @p1) ORDER BY [firstname] ASC OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY`,
+				"Oracle":     `SELECT * FROM "user" WHERE ("id" = :arg1) ORDER BY "firstname" ASC OFFSET :arg2 ROWS FETCH NEXT :arg3 ROWS ONLY`,
 			},
 			wantErr: false,
 		},
@@ -59,9 +85,19 @@ func TestRDBTranslator_TranslateFind(t1 *testing.T) {
 				),
 				WithPager(NewPageRequest(1, 10, false)),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "SELECT * FROM `user` WHERE ((`id` = ?) AND (`name` = ?)) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"MySQL":      "SELECT * FROM `user` WHERE ((`id` = ?) AND (`name` = ?)) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"PostgreSQL": `SELECT * FROM "user" WHERE (("id" = $1) AND ("name" = $2)) ORDER BY "firstname" ASC LIMIT $3 OFFSET $4`,
+				"SQLite":     `SELECT * FROM "user" WHERE (("id" = ?) AND ("name" = ?)) ORDER BY "firstname" ASC LIMIT ? OFFSET ?`,
+				"SQLServer":  `SELECT * FROM [user] WHERE (([id] = @p1) AND ([name] = @p2)) ORDER BY [firstname] ASC OFFSET @p3 ROWS FETCH NEXT @p4 ROWS ONLY`,
+				"Oracle":     `SELECT * FROM "user" WHERE (("id" = :arg1) AND ("name" = :arg2)) ORDER BY "firstname" ASC OFFSET :arg3 ROWS FETCH NEXT :arg4 ROWS ONLY`,
 			},
 			wantErr: false,
 		},
@@ -95,11 +131,33 @@ func TestRDBTranslator_TranslateFind(t1 *testing.T) {
 				),
 				WithPager(NewPageRequest(1, 10, false)),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
 				"MySQL": "SELECT * FROM `user` " +
 					"WHERE (((`id` = ?) AND (`name` LIKE CONCAT('%',?,'%'))) OR (`age` >= ?)) " +
 					"ORDER BY `firstname` ASC, `lastname` DESC LIMIT ?, ?",
+				"PostgreSQL": `SELECT * FROM "user" ` +
+					`WHERE ((("id" = $1) AND ("name" LIKE '%' || $2 || '%')) OR ("age" >= $3)) ` +
+					`ORDER BY "firstname" ASC, "lastname" DESC ` +
+					"LIMIT $4 OFFSET $5",
+				"SQLite": `SELECT * FROM "user" ` +
+					`WHERE ((("id" = ?) AND ("name" LIKE '%' || ? || '%')) OR ("age" >= ?)) ` +
+					`ORDER BY "firstname" ASC, "lastname" DESC ` +
+					"LIMIT ? OFFSET ?",
+				"SQLServer": `SELECT * FROM [user] ` +
+					`WHERE ((([id] = @p1) AND ([name] LIKE '%' + @p2 + '%')) OR ([age] >= @p3)) ` +
+					`ORDER BY [firstname] ASC, [lastname] DESC ` +
+					"OFFSET @p4 ROWS FETCH NEXT @p5 ROWS ONLY",
+				"Oracle": `SELECT * FROM "user" ` +
+					`WHERE ((("id" = :arg1) AND ("name" LIKE '%' || :arg2 || '%')) OR ("age" >= :arg3)) ` +
+					`ORDER BY "firstname" ASC, "lastname" DESC ` +
+					"OFFSET :arg4 ROWS FETCH NEXT :arg5 ROWS ONLY",
 			},
 			wantErr: false,
 		},
@@ -123,12 +181,333 @@ func TestRDBTranslator_TranslateFind(t1 *testing.T) {
 				),
 				WithPager(NewPageRequest(1, 10, false)),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL":      "SELECT DISTINCT * FROM `user` WHERE (`id` = ?) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"PostgreSQL": `SELECT DISTINCT * FROM "user" WHERE ("id" = $1) ORDER BY "firstname" ASC LIMIT $2 OFFSET $3`,
+				"SQLite":     `SELECT DISTINCT * FROM "user" WHERE ("id" = ?) ORDER BY "firstname" ASC LIMIT ? OFFSET ?`,
+				"SQLServer":  `SELECT DISTINCT * FROM [user] WHERE ([id] = @p1) ORDER BY [firstname] ASC OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY`,
+				"Oracle":     `SELECT DISTINCT * FROM "user" WHERE ("id" = :arg1) ORDER BY "firstname" ASC OFFSET :arg2 ROWS FETCH NEXT :arg3 ROWS ONLY`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "find top one filter one sort",
+			query: New(
+				SubjectFind,
+				WithSubjectModifier(SubjectModifierTop),
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Id", PredicateIs),
+						},
+						LogicOperatorAnd),
+				),
+				WithSorts(
+					[]*Sort{
+						NewSort("Firstname", DirectionAsc),
+					},
+				),
+				WithPager(NewPageRequest(1, 10, false)),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":     engine.NewMySQL(),
+				"SQLServer": engine.NewSQLServer(),
+				"Oracle":    engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL":     "SELECT * FROM `user` WHERE (`id` = ?) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"SQLServer": `SELECT TOP 10 * FROM [user] WHERE ([id] = @p1) ORDER BY [firstname] ASC`,
+				"Oracle":    `SELECT * FROM "user" WHERE ("id" = :arg1) ORDER BY "firstname" ASC OFFSET :arg2 ROWS FETCH NEXT :arg3 ROWS ONLY`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "find named args",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Id", PredicateBetween, WithFilterNamedArgs("min_id", "max_id")),
+							NewFilter("Name", PredicateIs, WithFilterNamedArgs("name")),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL":      "SELECT * FROM `user` WHERE ((`id` >= ? AND `id` <= ?) AND (`name` = ?))",
+				"PostgreSQL": `SELECT * FROM "user" WHERE (("id" >= $1 AND "id" <= $2) AND ("name" = $3))`,
+				"SQLServer":  `SELECT * FROM [user] WHERE (([id] >= @p1 AND [id] <= @p2) AND ([name] = @p3))`,
+				"Oracle":     `SELECT * FROM "user" WHERE (("id" >= :arg1 AND "id" <= :arg2) AND ("name" = :arg3))`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "find projection with alias",
+			query: New(
+				SubjectFind,
+				WithSubjectModifier(SubjectModifierDistinct),
+				WithTable(NewTable("user")),
+				WithProjection(NewProjectionWithFields([]*ProjectionField{
+					NewProjectionField("FirstName"),
+					NewProjectionField("Email", WithProjectionFieldAlias("mail")),
+				})),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Id", PredicateIs),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLServer":  engine.NewSQLServer(),
+			},
+			wantResults: map[string]string{
+				"MySQL":      "SELECT DISTINCT `first_name`, `email` AS `mail` FROM `user` WHERE (`id` = ?)",
+				"PostgreSQL": `SELECT DISTINCT "first_name", "email" AS "mail" FROM "user" WHERE ("id" = $1)`,
+				"SQLServer":  `SELECT DISTINCT [first_name], [email] AS [mail] FROM [user] WHERE ([id] = @p1)`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "find top projection from struct",
+			query: New(
+				SubjectFind,
+				WithSubjectModifier(SubjectModifierTop),
+				WithTable(NewTable("user")),
+				WithProjection(briefProjection),
+				WithPager(NewPageRequest(1, 1, false)),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":     engine.NewMySQL(),
+				"SQLServer": engine.NewSQLServer(),
+			},
+			wantResults: map[string]string{
+				"MySQL":     "SELECT `id`, `name`, `mail_address` FROM `user` LIMIT ?, ?",
+				"SQLServer": `SELECT TOP 1 [id], [name], [mail_address] FROM [user]`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "find group by",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Age", PredicateGT),
+						},
+						LogicOperatorAnd),
+				),
+				WithGroupBy([]string{"Status"}),
+				WithSorts(
+					[]*Sort{
+						NewSort("Status", DirectionAsc),
+					},
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":  engine.NewMySQL(),
+				"SQLite": engine.NewSQLite(),
+			},
+			wantResults: map[string]string{
+				"MySQL":  "SELECT `status` FROM `user` WHERE (`age` > ?) GROUP BY `status` ORDER BY `status` ASC",
+				"SQLite": `SELECT "status" FROM "user" WHERE ("age" > ?) GROUP BY "status" ORDER BY "status" ASC`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "find one filter pager without sort",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Id", PredicateIs),
+						},
+						LogicOperatorAnd),
+				),
+				WithPager(NewPageRequest(2, 10, false)),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":     engine.NewMySQL(),
+				"SQLServer": engine.NewSQLServer(),
+			},
+			wantResults: map[string]string{
+				"MySQL":     "SELECT * FROM `user` WHERE (`id` = ?) LIMIT ?, ?",
+				"SQLServer": `SELECT * FROM [user] WHERE ([id] = @p1) ORDER BY (SELECT NULL) OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "find is true is false",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Active", PredicateIsTrue),
+							NewFilter("Deleted", PredicateIsFalse),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL":      "SELECT * FROM `user` WHERE ((`active`) AND (!`deleted`))",
+				"PostgreSQL": `SELECT * FROM "user" WHERE (("active") AND (NOT "deleted"))`,
+				"SQLite":     `SELECT * FROM "user" WHERE (("active" = 1) AND ("deleted" = 0))`,
+				"SQLServer":  `SELECT * FROM [user] WHERE (([active] = 1) AND ([deleted] = 0))`,
+				"Oracle":     `SELECT * FROM "user" WHERE (("active" = 1) AND ("deleted" = 0))`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "find is empty is not empty",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Nickname", PredicateIsEmpty),
+							NewFilter("Tags", PredicateIsNotEmpty, WithFilterCollection(true)),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL": "SELECT * FROM `user` WHERE ((`nickname` IS NULL OR `nickname` = '') " +
+					"AND (`tags` IS NOT NULL AND JSON_LENGTH(`tags`) > 0))",
+				"PostgreSQL": `SELECT * FROM "user" WHERE (("nickname" IS NULL OR "nickname" = '') ` +
+					`AND ("tags" IS NOT NULL AND jsonb_array_length("tags"::jsonb) > 0))`,
+				"SQLite": `SELECT * FROM "user" WHERE (("nickname" IS NULL OR "nickname" = '') ` +
+					`AND ("tags" IS NOT NULL AND json_array_length("tags") > 0))`,
+				"SQLServer": `SELECT * FROM [user] WHERE (([nickname] IS NULL OR [nickname] = '') ` +
+					`AND ([tags] IS NOT NULL AND EXISTS (SELECT 1 FROM OPENJSON([tags]))))`,
+				"Oracle": `SELECT * FROM "user" WHERE (("nickname" IS NULL) ` +
+					`AND ("tags" IS NOT NULL AND JSON_EXISTS("tags", '$[0]')))`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "find matches",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Email", PredicateMatches),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"Oracle":     engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL":      "SELECT * FROM `user` WHERE (`email` REGEXP ?)",
+				"PostgreSQL": `SELECT * FROM "user" WHERE ("email" ~ $1)`,
+				"SQLite":     `SELECT * FROM "user" WHERE ("email" REGEXP ?)`,
+				"Oracle":     `SELECT * FROM "user" WHERE (REGEXP_LIKE("email", :arg1))`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "find ignore case",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Name", PredicateStartsWith, WithFilterModifier(FilterModifierIgnoreCase)),
+							NewFilter("Status", PredicateIn, WithFilterValues([]string{"A", "B"}),
+								WithFilterModifier(FilterModifierIgnoreCase)),
+							NewFilter("Email", PredicateMatches, WithFilterModifier(FilterModifierIgnoreCase)),
+							NewFilter("Nickname", PredicateIsNull, WithFilterModifier(FilterModifierIgnoreCase)),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "SELECT DISTINCT * FROM `user` WHERE (`id` = ?) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"MySQL": "SELECT * FROM `user` WHERE ((LOWER(`name`) LIKE CONCAT(LOWER(?),'%')) " +
+					"AND (LOWER(`status`) in (LOWER(?), LOWER(?))) AND (REGEXP_LIKE(`email`, ?, 'i')) " +
+					"AND (`nickname` IS NULL))",
+				"PostgreSQL": `SELECT * FROM "user" WHERE (("name" ILIKE $1 || '%') ` +
+					`AND (LOWER("status") in (LOWER($2), LOWER($3))) AND ("email" ~* $4) ` +
+					`AND ("nickname" IS NULL))`,
+				"SQLite": `SELECT * FROM "user" WHERE ((LOWER("name") LIKE LOWER(?) || '%') ` +
+					`AND (LOWER("status") in (LOWER(?), LOWER(?))) AND ("email" REGEXP '(?i)' || ?) ` +
+					`AND ("nickname" IS NULL))`,
+				"Oracle": `SELECT * FROM "user" WHERE ((LOWER("name") LIKE LOWER(:arg1) || '%') ` +
+					`AND (LOWER("status") in (LOWER(:arg2), LOWER(:arg3))) AND (REGEXP_LIKE("email", :arg4, 'i')) ` +
+					`AND ("nickname" IS NULL))`,
 			},
 			wantErr: false,
 		},
+		{
+			name: "find matches unsupported",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Email", PredicateMatches),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"SQLServer": engine.NewSQLServer(),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		for dialect, dbEngine := range tt.engines {
@@ -139,9 +518,12 @@ func TestRDBTranslator_TranslateFind(t1 *testing.T) {
 					t1.Errorf("TranslateFind() error = %v, wantErr %v", err, tt.wantErr)
 					return
 				}
+				if err != nil {
+					return
+				}
 				wantResult := tt.wantResults[dialect]
-				if gotResult != wantResult {
-					t1.Errorf("TranslateFind() \nactual = %v, \nexpect = %v", gotResult, wantResult)
+				if gotResult.SQL() != wantResult {
+					t1.Errorf("TranslateFind() \nactual = %v, \nexpect = %v", gotResult.SQL(), wantResult)
 				}
 			})
 		}
@@ -170,9 +552,19 @@ func TestRDBTranslator_TranslateCount(t1 *testing.T) {
 						LogicOperatorAnd),
 				),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "SELECT COUNT(*) FROM `user` WHERE (`id` = ?)",
+				"MySQL":      "SELECT COUNT(*) AS X FROM `user` WHERE (`id` = ?)",
+				"PostgreSQL": `SELECT COUNT(*) AS X FROM "user" WHERE ("id" = $1)`,
+				"SQLite":     `SELECT COUNT(*) AS X FROM "user" WHERE ("id" = ?)`,
+				"SQLServer":  `SELECT COUNT(*) AS X FROM [user] WHERE ([id] = @p1)`,
+				"Oracle":     `SELECT COUNT(*) AS X FROM "user" WHERE ("id" = :arg1)`,
 			},
 			wantErr: false,
 		},
@@ -190,9 +582,19 @@ func TestRDBTranslator_TranslateCount(t1 *testing.T) {
 						LogicOperatorAnd),
 				),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "SELECT COUNT(*) FROM `user` WHERE ((`id` = ?) AND (`name` = ?))",
+				"MySQL":      "SELECT COUNT(*) AS X FROM `user` WHERE ((`id` = ?) AND (`name` = ?))",
+				"PostgreSQL": `SELECT COUNT(*) AS X FROM "user" WHERE (("id" = $1) AND ("name" = $2))`,
+				"SQLite":     `SELECT COUNT(*) AS X FROM "user" WHERE (("id" = ?) AND ("name" = ?))`,
+				"SQLServer":  `SELECT COUNT(*) AS X FROM [user] WHERE (([id] = @p1) AND ([name] = @p2))`,
+				"Oracle":     `SELECT COUNT(*) AS X FROM "user" WHERE (("id" = :arg1) AND ("name" = :arg2))`,
 			},
 			wantErr: false,
 		},
@@ -219,10 +621,24 @@ func TestRDBTranslator_TranslateCount(t1 *testing.T) {
 						LogicOperatorOr),
 				),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "SELECT COUNT(*) FROM `user` " +
+				"MySQL": "SELECT COUNT(*) AS X FROM `user` " +
 					"WHERE (((`id` = ?) AND (`name` LIKE CONCAT('%',?,'%'))) OR (`age` >= ?))",
+				"PostgreSQL": `SELECT COUNT(*) AS X FROM "user" ` +
+					`WHERE ((("id" = $1) AND ("name" LIKE '%' || $2 || '%')) OR ("age" >= $3))`,
+				"SQLite": `SELECT COUNT(*) AS X FROM "user" ` +
+					`WHERE ((("id" = ?) AND ("name" LIKE '%' || ? || '%')) OR ("age" >= ?))`,
+				"SQLServer": `SELECT COUNT(*) AS X FROM [user] ` +
+					`WHERE ((([id] = @p1) AND ([name] LIKE '%' + @p2 + '%')) OR ([age] >= @p3))`,
+				"Oracle": `SELECT COUNT(*) AS X FROM "user" ` +
+					`WHERE ((("id" = :arg1) AND ("name" LIKE '%' || :arg2 || '%')) OR ("age" >= :arg3))`,
 			},
 			wantErr: false,
 		},
@@ -246,9 +662,37 @@ func TestRDBTranslator_TranslateCount(t1 *testing.T) {
 				),
 				WithPager(NewPageRequest(1, 10, false)),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL":      "SELECT COUNT(*) AS X FROM (SELECT DISTINCT * FROM `user` WHERE (`id` = ?)) T",
+				"PostgreSQL": `SELECT COUNT(*) AS X FROM (SELECT DISTINCT * FROM "user" WHERE ("id" = $1)) T`,
+				"SQLite":     `SELECT COUNT(*) AS X FROM (SELECT DISTINCT * FROM "user" WHERE ("id" = ?)) T`,
+				"SQLServer":  `SELECT COUNT(*) AS X FROM (SELECT DISTINCT * FROM [user] WHERE ([id] = @p1)) T`,
+				"Oracle":     `SELECT COUNT(*) AS X FROM (SELECT DISTINCT * FROM "user" WHERE ("id" = :arg1)) T`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "count distinct projection",
+			query: New(
+				SubjectCount,
+				WithSubjectModifier(SubjectModifierDistinct),
+				WithTable(NewTable("user")),
+				WithProjection(NewProjection("Name")),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "SELECT COUNT(DISTINCT *) FROM `user` WHERE (`id` = ?)",
+				"MySQL":      "SELECT COUNT(*) AS X FROM (SELECT DISTINCT `name` FROM `user`) T",
+				"PostgreSQL": `SELECT COUNT(*) AS X FROM (SELECT DISTINCT "name" FROM "user") T`,
 			},
 			wantErr: false,
 		},
@@ -263,8 +707,8 @@ func TestRDBTranslator_TranslateCount(t1 *testing.T) {
 					return
 				}
 				wantResult := tt.wantResults[dialect]
-				if gotResult != wantResult {
-					t1.Errorf("TranslateFind() \nactual = %v, \nexpect = %v", gotResult, wantResult)
+				if gotResult.SQL() != wantResult {
+					t1.Errorf("TranslateFind() \nactual = %v, \nexpect = %v", gotResult.SQL(), wantResult)
 				}
 			})
 		}
@@ -293,9 +737,19 @@ func TestRDBTranslator_TranslateExists(t1 *testing.T) {
 						LogicOperatorAnd),
 				),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "SELECT 1 FROM `user` WHERE (`id` = ?) LIMIT 0, 1",
+				"MySQL":      "SELECT 1 AS X FROM `user` WHERE (`id` = ?) LIMIT 0, 1",
+				"PostgreSQL": `SELECT 1 AS X FROM "user" WHERE ("id" = $1) LIMIT 1 OFFSET 0`,
+				"SQLite":     `SELECT 1 AS X FROM "user" WHERE ("id" = ?) LIMIT 1 OFFSET 0`,
+				"SQLServer":  `SELECT 1 AS X FROM [user] WHERE ([id] = @p1) ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY`,
+				"Oracle":     `SELECT 1 AS X FROM "user" WHERE ("id" = :arg1) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY`,
 			},
 			wantErr: false,
 		},
@@ -313,9 +767,19 @@ func TestRDBTranslator_TranslateExists(t1 *testing.T) {
 						LogicOperatorAnd),
 				),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "SELECT 1 FROM `user` WHERE ((`id` = ?) AND (`name` = ?)) LIMIT 0, 1",
+				"MySQL":      "SELECT 1 AS X FROM `user` WHERE ((`id` = ?) AND (`name` = ?)) LIMIT 0, 1",
+				"PostgreSQL": `SELECT 1 AS X FROM "user" WHERE (("id" = $1) AND ("name" = $2)) LIMIT 1 OFFSET 0`,
+				"SQLite":     `SELECT 1 AS X FROM "user" WHERE (("id" = ?) AND ("name" = ?)) LIMIT 1 OFFSET 0`,
+				"SQLServer":  `SELECT 1 AS X FROM [user] WHERE (([id] = @p1) AND ([name] = @p2)) ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY`,
+				"Oracle":     `SELECT 1 AS X FROM "user" WHERE (("id" = :arg1) AND ("name" = :arg2)) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY`,
 			},
 			wantErr: false,
 		},
@@ -342,11 +806,29 @@ func TestRDBTranslator_TranslateExists(t1 *testing.T) {
 						LogicOperatorOr),
 				),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "SELECT 1 FROM `user` " +
+				"MySQL": "SELECT 1 AS X FROM `user` " +
 					"WHERE (((`id` = ?) AND (`name` LIKE CONCAT('%',?,'%'))) OR (`age` >= ?)) " +
 					"LIMIT 0, 1",
+				"PostgreSQL": `SELECT 1 AS X FROM "user" ` +
+					`WHERE ((("id" = $1) AND ("name" LIKE '%' || $2 || '%')) OR ("age" >= $3)) ` +
+					"LIMIT 1 OFFSET 0",
+				"SQLite": `SELECT 1 AS X FROM "user" ` +
+					`WHERE ((("id" = ?) AND ("name" LIKE '%' || ? || '%')) OR ("age" >= ?)) ` +
+					"LIMIT 1 OFFSET 0",
+				"SQLServer": `SELECT 1 AS X FROM [user] ` +
+					`WHERE ((([id] = @p1) AND ([name] LIKE '%' + @p2 + '%')) OR ([age] >= @p3)) ` +
+					"ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY",
+				"Oracle": `SELECT 1 AS X FROM "user" ` +
+					`WHERE ((("id" = :arg1) AND ("name" LIKE '%' || :arg2 || '%')) OR ("age" >= :arg3)) ` +
+					"OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY",
 			},
 			wantErr: false,
 		},
@@ -361,8 +843,8 @@ func TestRDBTranslator_TranslateExists(t1 *testing.T) {
 					return
 				}
 				wantResult := tt.wantResults[dialect]
-				if gotResult != wantResult {
-					t1.Errorf("TranslateFind() \nactual = %v, \nexpect = %v", gotResult, wantResult)
+				if gotResult.SQL() != wantResult {
+					t1.Errorf("TranslateFind() \nactual = %v, \nexpect = %v", gotResult.SQL(), wantResult)
 				}
 			})
 		}
@@ -397,9 +879,44 @@ func TestRDBTranslator_TranslateDelete(t1 *testing.T) {
 				),
 				WithPager(NewPageRequest(1, 10, false)),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL":      "DELETE FROM `user` WHERE (`id` = ?) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"PostgreSQL": `DELETE FROM "user" WHERE ctid IN (SELECT ctid FROM "user" WHERE ("id" = $1) ORDER BY "firstname" ASC LIMIT $2 OFFSET $3)`,
+				"Oracle":     `DELETE FROM "user" WHERE ROWID IN (SELECT ROWID FROM "user" WHERE ("id" = :arg1) ORDER BY "firstname" ASC OFFSET :arg2 ROWS FETCH NEXT :arg3 ROWS ONLY)`,
+				"SQLite":     `DELETE FROM "user" WHERE rowid IN (SELECT rowid FROM "user" WHERE ("id" = ?) ORDER BY "firstname" ASC LIMIT ? OFFSET ?)`,
+				"SQLServer":  `WITH X AS (SELECT * FROM [user] WHERE ([id] = @p1) ORDER BY [firstname] ASC OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY) DELETE FROM X`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete top one filter",
+			query: New(
+				SubjectDelete,
+				WithSubjectModifier(SubjectModifierTop),
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Status", PredicateIs),
+						},
+						LogicOperatorAnd),
+				),
+				WithPager(NewPageRequest(1, 5, false)),
+			),
+			engines: map[string]engine.Engine{
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLServer":  engine.NewSQLServer(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "DELETE FROM `user` WHERE (`id` = ?) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"PostgreSQL": `DELETE FROM "user" WHERE ctid IN (SELECT ctid FROM "user" WHERE ("status" = $1) LIMIT $2 OFFSET $3)`,
+				"SQLServer":  `WITH X AS (SELECT TOP 5 * FROM [user] WHERE ([status] = @p1)) DELETE FROM X`,
 			},
 			wantErr: false,
 		},
@@ -423,9 +940,17 @@ func TestRDBTranslator_TranslateDelete(t1 *testing.T) {
 				),
 				WithPager(NewPageRequest(1, 10, false)),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
-				"MySQL": "DELETE FROM `user` WHERE ((`id` = ?) AND (`name` = ?)) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"MySQL":      "DELETE FROM `user` WHERE ((`id` = ?) AND (`name` = ?)) ORDER BY `firstname` ASC LIMIT ?, ?",
+				"PostgreSQL": `DELETE FROM "user" WHERE ctid IN (SELECT ctid FROM "user" WHERE (("id" = $1) AND ("name" = $2)) ORDER BY "firstname" ASC LIMIT $3 OFFSET $4)`,
+				"Oracle":     `DELETE FROM "user" WHERE ROWID IN (SELECT ROWID FROM "user" WHERE (("id" = :arg1) AND ("name" = :arg2)) ORDER BY "firstname" ASC OFFSET :arg3 ROWS FETCH NEXT :arg4 ROWS ONLY)`,
+				"SQLite":     `DELETE FROM "user" WHERE rowid IN (SELECT rowid FROM "user" WHERE (("id" = ?) AND ("name" = ?)) ORDER BY "firstname" ASC LIMIT ? OFFSET ?)`,
 			},
 			wantErr: false,
 		},
@@ -459,11 +984,28 @@ func TestRDBTranslator_TranslateDelete(t1 *testing.T) {
 				),
 				WithPager(NewPageRequest(1, 10, false)),
 			),
-			engines: map[string]engine.Engine{"MySQL": engine.NewMySQL()},
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"Oracle":     engine.NewOracle(),
+			},
 			wantResults: map[string]string{
 				"MySQL": "DELETE FROM `user` " +
 					"WHERE (((`id` = ?) AND (`name` LIKE CONCAT('%',?,'%'))) OR (`age` >= ?)) " +
 					"ORDER BY `firstname` ASC, `lastname` DESC LIMIT ?, ?",
+				"PostgreSQL": `DELETE FROM "user" WHERE ctid IN (SELECT ctid FROM "user" ` +
+					`WHERE ((("id" = $1) AND ("name" LIKE '%' || $2 || '%')) OR ("age" >= $3)) ` +
+					`ORDER BY "firstname" ASC, "lastname" DESC ` +
+					`LIMIT $4 OFFSET $5)`,
+				"Oracle": `DELETE FROM "user" WHERE ROWID IN (SELECT ROWID FROM "user" ` +
+					`WHERE ((("id" = :arg1) AND ("name" LIKE '%' || :arg2 || '%')) OR ("age" >= :arg3)) ` +
+					`ORDER BY "firstname" ASC, "lastname" DESC ` +
+					`OFFSET :arg4 ROWS FETCH NEXT :arg5 ROWS ONLY)`,
+				"SQLite": `DELETE FROM "user" WHERE rowid IN (SELECT rowid FROM "user" ` +
+					`WHERE ((("id" = ?) AND ("name" LIKE '%' || ? || '%')) OR ("age" >= ?)) ` +
+					`ORDER BY "firstname" ASC, "lastname" DESC ` +
+					`LIMIT ? OFFSET ?)`,
 			},
 			wantErr: false,
 		},
@@ -478,11 +1020,1039 @@ func TestRDBTranslator_TranslateDelete(t1 *testing.T) {
 					return
 				}
 				wantResult := tt.wantResults[dialect]
-				if gotResult != wantResult {
-					t1.Errorf("TranslateFind() \nactual = %v, \nexpect = %v", gotResult, wantResult)
+				if gotResult.SQL() != wantResult {
+					t1.Errorf("TranslateFind() \nactual = %v, \nexpect = %v", gotResult.SQL(), wantResult)
 				}
 			})
 		}
 
 	}
 }
+
+func TestRDBTranslator_TranslateAggregate(t1 *testing.T) {
+	tests := []struct {
+		name        string
+		query       *Query
+		engines     map[string]engine.Engine
+		wantResults map[string]string
+		wantErr     bool
+	}{
+		{
+			name: "sum one filter",
+			query: New(
+				SubjectSum,
+				WithAggregateField("Amount"),
+				WithTable(NewTable("order")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("UserId", PredicateIs),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLServer":  engine.NewSQLServer(),
+			},
+			wantResults: map[string]string{
+				"MySQL":      "SELECT COALESCE(SUM(`amount`), 0) AS X FROM `order` WHERE (`user_id` = ?)",
+				"PostgreSQL": `SELECT COALESCE(SUM("amount"), 0) AS X FROM "order" WHERE ("user_id" = $1)`,
+				"SQLServer":  `SELECT COALESCE(SUM([amount]), 0) AS X FROM [order] WHERE ([user_id] = @p1)`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "max one filter",
+			query: New(
+				SubjectMax,
+				WithAggregateField("CreatedAt"),
+				WithTable(NewTable("order")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Status", PredicateIs),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":  engine.NewMySQL(),
+				"Oracle": engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL":  "SELECT MAX(`created_at`) AS X FROM `order` WHERE (`status` = ?)",
+				"Oracle": `SELECT MAX("created_at") AS X FROM "order" WHERE ("status" = :arg1)`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "avg without filter",
+			query: New(
+				SubjectAvg,
+				WithAggregateField("Score"),
+				WithTable(NewTable("user")),
+			),
+			engines: map[string]engine.Engine{
+				"SQLite": engine.NewSQLite(),
+			},
+			wantResults: map[string]string{
+				"SQLite": `SELECT AVG("score") AS X FROM "user"`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "sum group by having order by",
+			query: New(
+				SubjectSum,
+				WithAggregateField("Amount"),
+				WithTable(NewTable("order")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Status", PredicateIs),
+						},
+						LogicOperatorAnd),
+				),
+				WithGroupBy([]string{"UserId"}),
+				WithHaving(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Sum", PredicateGT),
+						},
+						LogicOperatorAnd),
+				),
+				WithSorts(
+					[]*Sort{
+						NewSort("Sum", DirectionDesc),
+					},
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+			},
+			wantResults: map[string]string{
+				"MySQL": "SELECT `user_id`, COALESCE(SUM(`amount`), 0) AS X FROM `order` WHERE (`status` = ?) " +
+					"GROUP BY `user_id` HAVING (COALESCE(SUM(`amount`), 0) > ?) ORDER BY COALESCE(SUM(`amount`), 0) DESC",
+				"PostgreSQL": `SELECT "user_id", COALESCE(SUM("amount"), 0) AS X FROM "order" WHERE ("status" = $1) ` +
+					`GROUP BY "user_id" HAVING (COALESCE(SUM("amount"), 0) > $2) ORDER BY COALESCE(SUM("amount"), 0) DESC`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "count group by multiple fields",
+			query: New(
+				SubjectCount,
+				WithTable(NewTable("user")),
+				WithGroupBy([]string{"Status", "Type"}),
+			),
+			engines: map[string]engine.Engine{
+				"SQLServer": engine.NewSQLServer(),
+				"Oracle":    engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"SQLServer": `SELECT [status], [type], COUNT(*) AS X FROM [user] GROUP BY [status], [type]`,
+				"Oracle":    `SELECT "status", "type", COUNT(*) AS X FROM "user" GROUP BY "status", "type"`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "min without field",
+			query: New(
+				SubjectMin,
+				WithTable(NewTable("user")),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		for dialect, dbEngine := range tt.engines {
+			t1.Run(tt.name, func(t1 *testing.T) {
+				translator := NewRDBTranslator(dbEngine)
+				gotResult, err := translator.Translate(context.Background(), tt.query)
+				if (err != nil) != tt.wantErr {
+					t1.Errorf("TranslateAggregate() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if err != nil {
+					return
+				}
+				wantResult := tt.wantResults[dialect]
+				if gotResult.SQL() != wantResult {
+					t1.Errorf("TranslateAggregate() \nactual = %v, \nexpect = %v", gotResult.SQL(), wantResult)
+				}
+			})
+		}
+	}
+}
+
+func TestRDBTranslator_TranslateInsert(t1 *testing.T) {
+	type User struct {
+		Id    int64
+		Name  string
+		Email string `db:"mail_address"`
+	}
+	userInsertion, err := NewInsertionFromStruct(User{}, WithInsertionConflictFields("Id"))
+	if err != nil {
+		t1.Fatal(err)
+	}
+	err = userInsertion.FillRows([][]any{{int64(1), "Lily", "lily@example.com"}, {int64(2), "Lucy", nil}})
+	if err != nil {
+		t1.Fatal(err)
+	}
+	type Account struct {
+		Id   int64 `db:"id"`
+		Name string
+	}
+	accountInsertion, err := NewInsertionFromStruct(Account{}, WithInsertionConflictFields("Id"))
+	if err != nil {
+		t1.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		query    *Query
+		engines  map[string]engine.Engine
+		wantSQLs map[string]string
+		wantArgs map[string][]any
+		wantErr  bool
+	}{
+		{
+			name: "insert one row without values",
+			query: New(
+				SubjectInsert,
+				WithTable(NewTable("user")),
+				WithInsertion(NewInsertion([]string{"Name", "Email"})),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":     engine.NewMySQL(),
+				"SQLServer": engine.NewSQLServer(),
+				"Oracle":    engine.NewOracle(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL":     "INSERT INTO `user` (`name`, `email`) VALUES (?, ?)",
+				"SQLServer": `INSERT INTO [user] ([name], [email]) VALUES (@p1, @p2)`,
+				"Oracle":    `INSERT INTO "user" ("name", "email") VALUES (:arg1, :arg2)`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":     {nil, nil},
+				"SQLServer": {nil, nil},
+				"Oracle":    {nil, nil},
+			},
+			wantErr: false,
+		},
+		{
+			name: "insert batch",
+			query: New(
+				SubjectInsert,
+				WithTable(NewTable("user")),
+				WithInsertion(userInsertion),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL": "INSERT INTO `user` (`id`, `name`, `mail_address`) VALUES (?, ?, ?), (?, ?, ?)",
+				"PostgreSQL": `INSERT INTO "user" ("id", "name", "mail_address") ` +
+					`VALUES ($1, $2, $3), ($4, $5, $6)`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":      {int64(1), "Lily", "lily@example.com", int64(2), "Lucy", nil},
+				"PostgreSQL": {int64(1), "Lily", "lily@example.com", int64(2), "Lucy", nil},
+			},
+			wantErr: false,
+		},
+		{
+			name: "save batch",
+			query: New(
+				SubjectSave,
+				WithTable(NewTable("user")),
+				WithInsertion(userInsertion),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL": "INSERT INTO `user` (`id`, `name`, `mail_address`) VALUES (?, ?, ?), (?, ?, ?) " +
+					"ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `mail_address` = VALUES(`mail_address`)",
+				"PostgreSQL": `INSERT INTO "user" ("id", "name", "mail_address") VALUES ($1, $2, $3), ($4, $5, $6) ` +
+					`ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "mail_address" = EXCLUDED."mail_address"`,
+				"SQLite": `INSERT INTO "user" ("id", "name", "mail_address") VALUES (?, ?, ?), (?, ?, ?) ` +
+					`ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "mail_address" = EXCLUDED."mail_address"`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":      {int64(1), "Lily", "lily@example.com", int64(2), "Lucy", nil},
+				"PostgreSQL": {int64(1), "Lily", "lily@example.com", int64(2), "Lucy", nil},
+				"SQLite":     {int64(1), "Lily", "lily@example.com", int64(2), "Lucy", nil},
+			},
+			wantErr: false,
+		},
+		{
+			name: "save conflict field tagged",
+			query: New(
+				SubjectSave,
+				WithTable(NewTable("account")),
+				WithInsertion(accountInsertion),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL": "INSERT INTO `account` (`id`, `name`) VALUES (?, ?) " +
+					"ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)",
+				"PostgreSQL": `INSERT INTO "account" ("id", "name") VALUES ($1, $2) ` +
+					`ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":      {nil, nil},
+				"PostgreSQL": {nil, nil},
+			},
+			wantErr: false,
+		},
+		{
+			name: "save nothing to update",
+			query: New(
+				SubjectSave,
+				WithTable(NewTable("user_role")),
+				WithInsertion(NewInsertion([]string{"UserId", "RoleId"},
+					WithInsertionConflictFields("UserId", "RoleId"), WithInsertionNumRow(2))),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL": "INSERT INTO `user_role` (`user_id`, `role_id`) VALUES (?, ?), (?, ?) " +
+					"ON DUPLICATE KEY UPDATE `user_id` = VALUES(`user_id`)",
+				"PostgreSQL": `INSERT INTO "user_role" ("user_id", "role_id") VALUES ($1, $2), ($3, $4) ` +
+					`ON CONFLICT ("user_id", "role_id") DO NOTHING`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":      {nil, nil, nil, nil},
+				"PostgreSQL": {nil, nil, nil, nil},
+			},
+			wantErr: false,
+		},
+		{
+			name: "save unsupported",
+			query: New(
+				SubjectSave,
+				WithTable(NewTable("user")),
+				WithInsertion(userInsertion),
+			),
+			engines: map[string]engine.Engine{
+				"SQLServer": engine.NewSQLServer(),
+				"Oracle":    engine.NewOracle(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "save without conflict fields",
+			query: New(
+				SubjectSave,
+				WithTable(NewTable("user")),
+				WithInsertion(NewInsertion([]string{"Name"})),
+			),
+			engines: map[string]engine.Engine{
+				"PostgreSQL": engine.NewPostgreSQL(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "insert without insertion",
+			query: New(
+				SubjectInsert,
+				WithTable(NewTable("user")),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		for dialect, dbEngine := range tt.engines {
+			t1.Run(tt.name, func(t1 *testing.T) {
+				translator := NewRDBTranslator(dbEngine)
+				gotResult, err := translator.Translate(context.Background(), tt.query)
+				if (err != nil) != tt.wantErr {
+					t1.Errorf("TranslateInsert() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if err != nil {
+					return
+				}
+				if gotResult.SQL() != tt.wantSQLs[dialect] {
+					t1.Errorf("TranslateInsert() \nactual = %v, \nexpect = %v", gotResult.SQL(), tt.wantSQLs[dialect])
+				}
+				if !reflect.DeepEqual(gotResult.Args(), tt.wantArgs[dialect]) {
+					t1.Errorf("TranslateInsert() \nactual args = %#v, \nexpect args = %#v", gotResult.Args(), tt.wantArgs[dialect])
+				}
+			})
+		}
+	}
+}
+
+func TestRDBTranslator_TranslateNestedField(t1 *testing.T) {
+	query := New(
+		SubjectFind,
+		WithTable(NewTable("user")),
+		WithFilterGroup(
+			NewFilterGroupWithFilters(
+				[]*Filter{
+					NewFilter("Address_City", PredicateIs),
+					NewFilter("Address_Geo_Zip", PredicateStartsWith),
+				},
+				LogicOperatorAnd),
+		),
+		WithSorts(
+			[]*Sort{
+				NewSort("Address_City", DirectionDesc),
+			},
+		),
+	)
+	type Audit struct {
+		CreatedAt string `db:"created_at"`
+	}
+	projection, err := NewProjectionFromStruct(Audit{})
+	if err != nil {
+		t1.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		mode        NestedFieldMode
+		query       *Query
+		engines     map[string]engine.Engine
+		wantResults map[string]string
+		wantErr     bool
+	}{
+		{
+			name: "flatten",
+			mode: NestedFieldFlatten,
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantResults: map[string]string{
+				"MySQL": "SELECT * FROM `user` WHERE ((`address_city` = ?) AND (`address_geo_zip` LIKE CONCAT(?,'%'))) " +
+					"ORDER BY `address_city` DESC",
+			},
+		},
+		{
+			name: "join",
+			mode: NestedFieldJoin,
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "json projection of the db tag",
+			mode:  NestedFieldJSON,
+			query: New(SubjectFind, WithTable(NewTable("user")), WithProjection(projection)),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantResults: map[string]string{
+				"MySQL": "SELECT `created_at` FROM `user`",
+			},
+		},
+		{
+			name: "json",
+			mode: NestedFieldJSON,
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+				"SQLServer":  engine.NewSQLServer(),
+				"Oracle":     engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL": "SELECT * FROM `user` WHERE ((`address`->>'$.city' = ?) " +
+					"AND (`address`->>'$.geo.zip' LIKE CONCAT(?,'%'))) ORDER BY `address`->>'$.city' DESC",
+				"PostgreSQL": `SELECT * FROM "user" WHERE (("address"->>'city' = $1) ` +
+					`AND ("address"#>>'{geo,zip}' LIKE $2 || '%')) ORDER BY "address"->>'city' DESC`,
+				"SQLite": `SELECT * FROM "user" WHERE (("address"->>'$.city' = ?) ` +
+					`AND ("address"->>'$.geo.zip' LIKE ? || '%')) ORDER BY "address"->>'$.city' DESC`,
+				"SQLServer": `SELECT * FROM [user] WHERE ((JSON_VALUE([address], '$.city') = @p1) ` +
+					`AND (JSON_VALUE([address], '$.geo.zip') LIKE @p2 + '%')) ORDER BY JSON_VALUE([address], '$.city') DESC`,
+				"Oracle": `SELECT * FROM "user" WHERE ((JSON_VALUE("address", '$.city') = :arg1) ` +
+					`AND (JSON_VALUE("address", '$.geo.zip') LIKE :arg2 || '%')) ORDER BY JSON_VALUE("address", '$.city') DESC`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		for dialect, dbEngine := range tt.engines {
+			t1.Run(tt.name, func(t1 *testing.T) {
+				translator := NewRDBTranslator(dbEngine, WithNestedFieldMode(tt.mode))
+				q := query
+				if tt.query != nil {
+					q = tt.query
+				}
+				gotResult, err := translator.Translate(context.Background(), q)
+				if (err != nil) != tt.wantErr {
+					t1.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if err != nil {
+					return
+				}
+				wantResult := tt.wantResults[dialect]
+				if gotResult.SQL() != wantResult {
+					t1.Errorf("Translate() \nactual = %v, \nexpect = %v", gotResult.SQL(), wantResult)
+				}
+			})
+		}
+	}
+}
+
+func TestRDBTranslator_TranslateJoins(t1 *testing.T) {
+	orderJoins := []*Join{
+		NewJoin("Customer", NewTable("customer"), []*JoinCondition{
+			NewJoinCondition("CustomerId", "Id"),
+		}),
+		NewJoin("Customer_Address", NewTable("address"), []*JoinCondition{
+			NewJoinCondition("AddressId", "Id"),
+		}, WithJoinType(JoinTypeLeft)),
+	}
+	tests := []struct {
+		name        string
+		query       *Query
+		engines     map[string]engine.Engine
+		wantResults map[string]string
+		wantErr     bool
+	}{
+		{
+			name: "find joined nested fields",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("order")),
+				WithJoins(orderJoins),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Customer_Name", PredicateIs),
+							NewFilter("Customer_Address_City", PredicateIs),
+							NewFilter("Status", PredicateIs),
+						},
+						LogicOperatorAnd),
+				),
+				WithSorts(
+					[]*Sort{
+						NewSort("CreatedAt", DirectionDesc),
+					},
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"Oracle":     engine.NewOracle(),
+			},
+			wantResults: map[string]string{
+				"MySQL": "SELECT `order`.* FROM `order` " +
+					"INNER JOIN `customer` `customer` ON `order`.`customer_id` = `customer`.`id` " +
+					"LEFT JOIN `address` `customer_address` ON `customer`.`address_id` = `customer_address`.`id` " +
+					"WHERE ((`customer`.`name` = ?) AND (`customer_address`.`city` = ?) AND (`order`.`status` = ?)) " +
+					"ORDER BY `order`.`created_at` DESC",
+				"PostgreSQL": `SELECT "order".* FROM "order" ` +
+					`INNER JOIN "customer" "customer" ON "order"."customer_id" = "customer"."id" ` +
+					`LEFT JOIN "address" "customer_address" ON "customer"."address_id" = "customer_address"."id" ` +
+					`WHERE (("customer"."name" = $1) AND ("customer_address"."city" = $2) AND ("order"."status" = $3)) ` +
+					`ORDER BY "order"."created_at" DESC`,
+				"Oracle": `SELECT "order".* FROM "order" ` +
+					`INNER JOIN "customer" "customer" ON "order"."customer_id" = "customer"."id" ` +
+					`LEFT JOIN "address" "customer_address" ON "customer"."address_id" = "customer_address"."id" ` +
+					`WHERE (("customer"."name" = :arg1) AND ("customer_address"."city" = :arg2) AND ("order"."status" = :arg3)) ` +
+					`ORDER BY "order"."created_at" DESC`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "sum joined group by",
+			query: New(
+				SubjectSum,
+				WithAggregateField("Amount"),
+				WithTable(NewTable("order")),
+				WithJoins(orderJoins[:1]),
+				WithGroupBy([]string{"Customer_Name"}),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantResults: map[string]string{
+				"MySQL": "SELECT `customer`.`name`, COALESCE(SUM(`order`.`amount`), 0) AS X FROM `order` " +
+					"INNER JOIN `customer` `customer` ON `order`.`customer_id` = `customer`.`id` " +
+					"GROUP BY `customer`.`name`",
+			},
+			wantErr: false,
+		},
+		{
+			name: "join without parent",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("order")),
+				WithJoins(orderJoins[1:]),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "delete joined",
+			query: New(
+				SubjectDelete,
+				WithTable(NewTable("order")),
+				WithJoins(orderJoins[:1]),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		for dialect, dbEngine := range tt.engines {
+			t1.Run(tt.name, func(t1 *testing.T) {
+				translator := NewRDBTranslator(dbEngine)
+				gotResult, err := translator.Translate(context.Background(), tt.query)
+				if (err != nil) != tt.wantErr {
+					t1.Errorf("TranslateJoins() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if err != nil {
+					return
+				}
+				wantResult := tt.wantResults[dialect]
+				if gotResult.SQL() != wantResult {
+					t1.Errorf("TranslateJoins() \nactual = %v, \nexpect = %v", gotResult.SQL(), wantResult)
+				}
+			})
+		}
+	}
+}
+
+func TestRDBTranslator_TranslateKeyset(t1 *testing.T) {
+	statusFilter := NewFilterGroupWithFilters(
+		[]*Filter{
+			NewFilter("Status", PredicateIs, WithFilterValues(1)),
+		},
+		LogicOperatorAnd)
+	tests := []struct {
+		name     string
+		query    *Query
+		engines  map[string]engine.Engine
+		wantSQLs map[string]string
+		wantArgs map[string][]any
+		wantErr  bool
+	}{
+		{
+			name: "find first page",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(statusFilter),
+				WithSorts([]*Sort{NewSort("Id", DirectionAsc)}),
+				WithPager(NewKeysetRequest(10)),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL":      "SELECT * FROM `user` WHERE (`status` = ?) ORDER BY `id` ASC LIMIT ?, ?",
+				"PostgreSQL": `SELECT * FROM "user" WHERE ("status" = $1) ORDER BY "id" ASC LIMIT $2 OFFSET $3`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":      {1, 0, 10},
+				"PostgreSQL": {1, 10, 0},
+			},
+			wantErr: false,
+		},
+		{
+			name: "find after single key",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithSorts([]*Sort{NewSort("Id", DirectionDesc)}),
+				WithPager(NewKeysetRequest(10, int64(100))),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":     engine.NewMySQL(),
+				"SQLServer": engine.NewSQLServer(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL": "SELECT * FROM `user` WHERE (`id` < ?) ORDER BY `id` DESC LIMIT ?, ?",
+				"SQLServer": `SELECT * FROM [user] WHERE ([id] < @p1) ORDER BY [id] DESC ` +
+					`OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":     {int64(100), 0, 10},
+				"SQLServer": {int64(100), 0, 10},
+			},
+			wantErr: false,
+		},
+		{
+			name: "find after row value",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(statusFilter),
+				WithSorts([]*Sort{NewSort("CreatedAt", DirectionDesc), NewSort("Id", DirectionDesc)}),
+				WithPager(NewKeysetRequest(10, "2022-10-01", int64(100))),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLServer":  engine.NewSQLServer(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL": "SELECT * FROM `user` WHERE (`status` = ?) AND ((`created_at`, `id`) < (?, ?)) " +
+					"ORDER BY `created_at` DESC, `id` DESC LIMIT ?, ?",
+				"PostgreSQL": `SELECT * FROM "user" WHERE ("status" = $1) AND (("created_at", "id") < ($2, $3)) ` +
+					`ORDER BY "created_at" DESC, "id" DESC LIMIT $4 OFFSET $5`,
+				"SQLServer": `SELECT * FROM [user] WHERE ([status] = @p1) ` +
+					`AND ([created_at] < @p2 OR ([created_at] = @p3 AND [id] < @p4)) ` +
+					`ORDER BY [created_at] DESC, [id] DESC OFFSET @p5 ROWS FETCH NEXT @p6 ROWS ONLY`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":      {1, "2022-10-01", int64(100), 0, 10},
+				"PostgreSQL": {1, "2022-10-01", int64(100), 10, 0},
+				"SQLServer":  {1, "2022-10-01", "2022-10-01", int64(100), 0, 10},
+			},
+			wantErr: false,
+		},
+		{
+			name: "find after mixed directions",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithSorts([]*Sort{
+					NewSort("Age", DirectionDesc),
+					NewSort("Name", DirectionAsc),
+					NewSort("Id", DirectionAsc),
+				}),
+				WithPager(NewKeysetRequest(10, 18, "Lily", int64(100))),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL": "SELECT * FROM `user` " +
+					"WHERE (`age` < ? OR (`age` = ? AND `name` > ?) OR (`age` = ? AND `name` = ? AND `id` > ?)) " +
+					"ORDER BY `age` DESC, `name` ASC, `id` ASC LIMIT ?, ?",
+			},
+			wantArgs: map[string][]any{
+				"MySQL": {18, 18, "Lily", 18, "Lily", int64(100), 0, 10},
+			},
+			wantErr: false,
+		},
+		{
+			name: "find without sorts",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithPager(NewKeysetRequest(10)),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "find keys mismatch sorts",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithSorts([]*Sort{NewSort("CreatedAt", DirectionDesc), NewSort("Id", DirectionDesc)}),
+				WithPager(NewKeysetRequest(10, int64(100))),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		for dialect, dbEngine := range tt.engines {
+			t1.Run(tt.name, func(t1 *testing.T) {
+				translator := NewRDBTranslator(dbEngine)
+				gotResult, err := translator.Translate(context.Background(), tt.query)
+				if (err != nil) != tt.wantErr {
+					t1.Errorf("TranslateKeyset() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if err != nil {
+					return
+				}
+				wantSQL := tt.wantSQLs[dialect]
+				if gotResult.SQL() != wantSQL {
+					t1.Errorf("TranslateKeyset() \nactual = %v, \nexpect = %v", gotResult.SQL(), wantSQL)
+				}
+				wantArgs := tt.wantArgs[dialect]
+				if !reflect.DeepEqual(gotResult.Args(), wantArgs) {
+					t1.Errorf("TranslateKeyset() \nactual = %#v, \nexpect = %#v", gotResult.Args(), wantArgs)
+				}
+			})
+		}
+	}
+}
+
+func TestRDBTranslator_TranslateArgs(t1 *testing.T) {
+	tests := []struct {
+		name     string
+		query    *Query
+		engines  map[string]engine.Engine
+		wantSQLs map[string]string
+		wantArgs map[string][]any
+		// namedValues binds the named args, the bound args are checked by wantArgs if it is set
+		namedValues map[string]any
+		// wantBindings is checked if it is set
+		wantBindings map[string][]Binding
+		wantErr      bool
+	}{
+		{
+			name: "find values and pager",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Age", PredicateBetween, WithFilterValues(18, 30)),
+							NewFilter("Name", PredicateContains, WithFilterValues("Lily")),
+						},
+						LogicOperatorAnd),
+				),
+				WithPager(NewPageRequest(3, 10, false)),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLite":     engine.NewSQLite(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL": "SELECT * FROM `user` " +
+					"WHERE ((`age` >= ? AND `age` <= ?) AND (`name` LIKE CONCAT('%',?,'%'))) LIMIT ?, ?",
+				"PostgreSQL": `SELECT * FROM "user" ` +
+					`WHERE (("age" >= $1 AND "age" <= $2) AND ("name" LIKE '%' || $3 || '%')) LIMIT $4 OFFSET $5`,
+				"SQLite": `SELECT * FROM "user" ` +
+					`WHERE (("age" >= ? AND "age" <= ?) AND ("name" LIKE '%' || ? || '%')) LIMIT ? OFFSET ?`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":      {18, 30, "Lily", 20, 10},
+				"PostgreSQL": {18, 30, "Lily", 10, 20},
+				"SQLite":     {18, 30, "Lily", 10, 20},
+			},
+			wantErr: false,
+		},
+		{
+			name: "find in flattened",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Id", PredicateIn, WithFilterValues([]int64{1, 2, 3})),
+							NewFilter("Status", PredicateNotIn, WithFilterValues([]string{})),
+							NewFilter("Name", PredicateIs, WithFilterValues("Lily")),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL":      "SELECT * FROM `user` WHERE ((`id` in (?, ?, ?)) AND (1 = 1) AND (`name` = ?))",
+				"PostgreSQL": `SELECT * FROM "user" WHERE (("id" in ($1, $2, $3)) AND (1 = 1) AND ("name" = $4))`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":      {int64(1), int64(2), int64(3), "Lily"},
+				"PostgreSQL": {int64(1), int64(2), int64(3), "Lily"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "update set args precede filter args",
+			query: New(
+				SubjectUpdate,
+				WithTable(NewTable("user")),
+				WithAssignments([]*Assignment{
+					NewAssignment("Name", WithAssignmentValue("Lily")),
+					NewAssignment("Email", WithAssignmentValue("lily@example.com")),
+				}),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Id", PredicateIn, WithFilterValues([]int64{1, 2})),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLServer":  engine.NewSQLServer(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL":      "UPDATE `user` SET `name` = ?, `email` = ? WHERE (`id` in (?, ?))",
+				"PostgreSQL": `UPDATE "user" SET "name" = $1, "email" = $2 WHERE ("id" in ($3, $4))`,
+				"SQLServer":  `UPDATE [user] SET [name] = @p1, [email] = @p2 WHERE ([id] in (@p3, @p4))`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":      {"Lily", "lily@example.com", int64(1), int64(2)},
+				"PostgreSQL": {"Lily", "lily@example.com", int64(1), int64(2)},
+				"SQLServer":  {"Lily", "lily@example.com", int64(1), int64(2)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "update named args",
+			query: New(
+				SubjectUpdate,
+				WithTable(NewTable("user")),
+				WithAssignments([]*Assignment{
+					NewAssignment("Status", WithAssignmentNamedArg("status")),
+				}),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Id", PredicateIs, WithFilterNamedArgs("id")),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"Oracle": engine.NewOracle(),
+			},
+			wantSQLs: map[string]string{
+				"Oracle": `UPDATE "user" SET "status" = :arg1 WHERE ("id" = :arg2)`,
+			},
+			wantArgs: map[string][]any{
+				"Oracle": nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "update values and named args",
+			query: New(
+				SubjectUpdate,
+				WithTable(NewTable("user")),
+				WithAssignments([]*Assignment{
+					NewAssignment("Name", WithAssignmentValue("Lily")),
+				}),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Id", PredicateIs, WithFilterNamedArgs("id")),
+						},
+						LogicOperatorAnd),
+				),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantSQLs: map[string]string{
+				"MySQL": "UPDATE `user` SET `name` = ? WHERE (`id` = ?)",
+			},
+			wantArgs: map[string][]any{
+				"MySQL": {"Lily"},
+			},
+			wantBindings: map[string][]Binding{
+				"MySQL": {NewValueBinding("Lily"), NewNamedBinding("id")},
+			},
+			wantErr: false,
+		},
+		{
+			name: "find named args and pager",
+			query: New(
+				SubjectFind,
+				WithTable(NewTable("user")),
+				WithFilterGroup(
+					NewFilterGroupWithFilters(
+						[]*Filter{
+							NewFilter("Name", PredicateIs, WithFilterNamedArgs("name")),
+						},
+						LogicOperatorAnd),
+				),
+				WithPager(NewPageRequest(3, 10, false)),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL":      engine.NewMySQL(),
+				"PostgreSQL": engine.NewPostgreSQL(),
+				"SQLServer":  engine.NewSQLServer(),
+			},
+			namedValues: map[string]any{"name": "Lily"},
+			wantSQLs: map[string]string{
+				"MySQL":      "SELECT * FROM `user` WHERE (`name` = ?) LIMIT ?, ?",
+				"PostgreSQL": `SELECT * FROM "user" WHERE ("name" = $1) LIMIT $2 OFFSET $3`,
+				"SQLServer": `SELECT * FROM [user] WHERE ([name] = @p1) ORDER BY (SELECT NULL) ` +
+					`OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY`,
+			},
+			wantArgs: map[string][]any{
+				"MySQL":      {"Lily", 20, 10},
+				"PostgreSQL": {"Lily", 10, 20},
+				"SQLServer":  {"Lily", 20, 10},
+			},
+			wantBindings: map[string][]Binding{
+				"PostgreSQL": {NewNamedBinding("name"), NewValueBinding(10), NewValueBinding(20)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "update without set clause",
+			query: New(
+				SubjectUpdate,
+				WithTable(NewTable("user")),
+			),
+			engines: map[string]engine.Engine{
+				"MySQL": engine.NewMySQL(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		for dialect, dbEngine := range tt.engines {
+			t1.Run(tt.name, func(t1 *testing.T) {
+				translator := NewRDBTranslator(dbEngine)
+				gotResult, err := translator.Translate(context.Background(), tt.query)
+				if (err != nil) != tt.wantErr {
+					t1.Errorf("Translate() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if err != nil {
+					return
+				}
+				if gotResult.SQL() != tt.wantSQLs[dialect] {
+					t1.Errorf("Translate() \nactual = %v, \nexpect = %v", gotResult.SQL(), tt.wantSQLs[dialect])
+				}
+				gotArgs := gotResult.Args()
+				if tt.namedValues != nil {
+					if gotArgs, err = gotResult.BindNamedArgs(tt.namedValues); err != nil {
+						t1.Errorf("BindNamedArgs() error = %v", err)
+						return
+					}
+				}
+				if !reflect.DeepEqual(gotArgs, tt.wantArgs[dialect]) {
+					t1.Errorf("Translate() \nactual args = %#v, \nexpect args = %#v", gotArgs, tt.wantArgs[dialect])
+				}
+				if tt.wantBindings[dialect] != nil && !reflect.DeepEqual(gotResult.Bindings(), tt.wantBindings[dialect]) {
+					t1.Errorf("Translate() \nactual bindings = %#v, \nexpect bindings = %#v",
+						gotResult.Bindings(), tt.wantBindings[dialect])
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"github.com/gomelon/melon/data/query"
+	"reflect"
+	"sync"
+)
+
+// Repository executes the query parsed from the method name at runtime, it is an alternative to the generated one,
+// the parsed queries are cached by the method name
+type Repository struct {
+	tm             *SQLTXManager
+	translator     query.Translator
+	parser         *RuleParser
+	scanner        *Scanner
+	conflictFields []string
+	sortFields     []string
+	queries        sync.Map
+}
+
+func NewRepository(tm *SQLTXManager, translator query.Translator, opts ...RepositoryOption) *Repository {
+	repository := &Repository{
+		tm:             tm,
+		translator:     translator,
+		parser:         NewRuleParser(),
+		scanner:        defaultScanner,
+		conflictFields: []string{"Id"},
+	}
+	for _, opt := range opts {
+		opt(repository)
+	}
+	return repository
+}
+
+// Execute parses the method, executes the query on the table with the args, and stores the result in the dest,
+// the args are the values of the assignments and the filters in order, the entity or entities for Insert and Save,
+// and query.Pager, *query.SortSpec or []*query.Sort for Find in any position.
+// The dest is decided by the subject:
+//
+//	Find:                  *[]T for all rows, or *T for the first row and returns sql.ErrNoRows if there is no row,
+//	                       T is a struct, a pointer to struct, map[string]any or a type scanned from the only column
+//	Count:                 *int64 or other numeric types
+//	Exists:                *bool
+//	Sum Avg Min Max:       the type scanned from the aggregate value
+//	GroupBy:               *[]T for Count and Sum Avg Min Max, T is mapped from the grouping columns and the value X
+//	Delete Update Insert Save: *int64 of the rows affected, or nil
+func (r *Repository) Execute(ctx context.Context, method string, table query.Table, dest any, args ...any) error {
+	q, err := r.Parse(method)
+	if err != nil {
+		return err
+	}
+	q = q.With(query.WithTable(table))
+
+	values := make([]any, 0, len(args))
+	var pager query.Pager
+	var sortSpec *query.SortSpec
+	for _, arg := range args {
+		switch typedArg := arg.(type) {
+		case query.Pager:
+			pager = typedArg
+		case *query.SortSpec:
+			sortSpec = typedArg
+		case []*query.Sort:
+			sortSpec = query.NewSortSpec(typedArg)
+		default:
+			values = append(values, arg)
+		}
+	}
+	subject := q.Subject()
+	if (pager != nil || sortSpec != nil) && subject != query.SubjectFind {
+		return fmt.Errorf("execute [%s] fail: the pager and the sorts are only for find", method)
+	}
+	if pager != nil {
+		q = q.With(query.WithPager(pager))
+	}
+	if subject.Insertable() {
+		if len(values) != 1 {
+			return fmt.Errorf("execute [%s] fail: expected the entity or the entities, but actual %d values",
+				method, len(values))
+		}
+		var opts []query.InsertionOption
+		if subject == query.SubjectSave {
+			opts = append(opts, query.WithInsertionConflictFields(r.conflictFields...))
+		}
+		insertion, err := query.NewInsertionFromValues(values[0], opts...)
+		if err != nil {
+			return err
+		}
+		q = q.With(query.WithInsertion(insertion))
+	} else {
+		if q, err = q.Bind(values...); err != nil {
+			return err
+		}
+	}
+	if q, err = q.WithSortSpec(sortSpec, r.sortFields...); err != nil {
+		return err
+	}
+
+	executor := r.tm.OriginTXOrDB(ctx)
+	destValue := reflect.ValueOf(dest)
+	if dest != nil && (destValue.Kind() != reflect.Pointer || destValue.IsNil()) {
+		return fmt.Errorf("execute [%s] fail: the dest must be a non-nil pointer", method)
+	}
+	if dest == nil && !subject.Insertable() && subject != query.SubjectDelete && subject != query.SubjectUpdate {
+		return fmt.Errorf("execute [%s] fail: the dest of subject [%s] is required", method, subject.Name())
+	}
+
+	switch {
+	case subject == query.SubjectFind:
+		return r.find(ctx, executor, q, destValue.Elem())
+	case len(q.GroupBy()) > 0:
+		return r.findGroups(ctx, executor, method, q, destValue.Elem())
+	case subject == query.SubjectCount:
+		total, err := Count(ctx, executor, r.translator, q)
+		if err != nil {
+			return err
+		}
+		return setResult(destValue.Elem(), total)
+	case subject == query.SubjectExists:
+		exists, err := Exists(ctx, executor, r.translator, q)
+		if err != nil {
+			return err
+		}
+		return setResult(destValue.Elem(), exists)
+	case subject.IsAggregate():
+		return scanValue(ctx, executor, r.translator, q, dest)
+	default:
+		rowsAffected, err := Exec(ctx, executor, r.translator, q)
+		if err != nil || dest == nil {
+			return err
+		}
+		return setResult(destValue.Elem(), rowsAffected)
+	}
+}
+
+// Parse returns the query parsed from the method, the query is cached and shared, bind it before filling values
+func (r *Repository) Parse(method string) (*query.Query, error) {
+	if q, ok := r.queries.Load(method); ok {
+		return q.(*query.Query), nil
+	}
+	q, err := r.parser.Parse(method)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := r.queries.LoadOrStore(method, q)
+	return actual.(*query.Query), nil
+}
+
+// findGroups the grouped Count and aggregates return a row for each group, so the dest must be a slice
+func (r *Repository) findGroups(ctx context.Context, executor SQLExecutor, method string, q *query.Query,
+	dest reflect.Value) error {
+
+	if dest.Kind() != reflect.Slice || dest.Type().Elem().Kind() == reflect.Uint8 {
+		return fmt.Errorf("execute [%s] fail: the dest of the grouped query must be a pointer to slice", method)
+	}
+	values, err := FindGroups(ctx, executor, r.translator, q, r.scanner.valueMapper(dest.Type().Elem()))
+	if err != nil {
+		return err
+	}
+	slice := reflect.MakeSlice(dest.Type(), 0, len(values))
+	slice = reflect.Append(slice, values...)
+	dest.Set(slice)
+	return nil
+}
+
+func (r *Repository) find(ctx context.Context, executor SQLExecutor, q *query.Query, dest reflect.Value) error {
+	if dest.Kind() == reflect.Slice && dest.Type().Elem().Kind() != reflect.Uint8 {
+		values, err := FindAll(ctx, executor, r.translator, q, r.scanner.valueMapper(dest.Type().Elem()))
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(dest.Type(), 0, len(values))
+		slice = reflect.Append(slice, values...)
+		dest.Set(slice)
+		return nil
+	}
+	value, err := FindOne(ctx, executor, r.translator, q, r.scanner.valueMapper(dest.Type()))
+	if err != nil {
+		return err
+	}
+	dest.Set(value)
+	return nil
+}
+
+// setResult sets the numeric or bool result to the dest of the convertible type
+func setResult(dest reflect.Value, result any) error {
+	resultValue := reflect.ValueOf(result)
+	if !resultValue.CanConvert(dest.Type()) {
+		return fmt.Errorf("set result fail: %T can not be converted to %v", result, dest.Type())
+	}
+	dest.Set(resultValue.Convert(dest.Type()))
+	return nil
+}
+
+type RepositoryOption func(repository *Repository)
+
+// WithRepositoryConflictFields the conflict fields of Save, default is Id
+func WithRepositoryConflictFields(fieldNames ...string) RepositoryOption {
+	return func(repository *Repository) {
+		repository.conflictFields = fieldNames
+	}
+}
+
+// WithRepositorySortFields the fields allowed by the dynamic sorts of Find, default allows any field
+func WithRepositorySortFields(fieldNames ...string) RepositoryOption {
+	return func(repository *Repository) {
+		repository.sortFields = fieldNames
+	}
+}
+
+// WithRepositoryScanner the scanner of the Find results, default scans the columns in snake_case
+func WithRepositoryScanner(scanner *Scanner) RepositoryOption {
+	return func(repository *Repository) {
+		repository.scanner = scanner
+	}
+}
@@ -0,0 +1,178 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"github.com/gomelon/melon/data/engine"
+	"github.com/gomelon/melon/data/query"
+	"reflect"
+	"testing"
+)
+
+type repositoryUser struct {
+	Id        int64
+	Name      string
+	CreatedAt string
+}
+
+func TestRepository_Execute(t *testing.T) {
+	fake := newFakeDB()
+	fake.returns("SELECT * FROM `user` WHERE (`status` = ?) ORDER BY `name` ASC LIMIT ?, ?",
+		[]string{"id", "name"},
+		[]driver.Value{int64(1), "Lily"}, []driver.Value{int64(2), "Lucy"},
+	)
+	fake.returns("SELECT * FROM `user` WHERE (`id` = ?)", []string{"id", "name"},
+		[]driver.Value{int64(1), "Lily"},
+	)
+	fake.returns("SELECT `name` FROM `user` WHERE (`id` in (?, ?))", []string{"name"},
+		[]driver.Value{"Lily"}, []driver.Value{"Lucy"},
+	)
+	fake.returns("SELECT COUNT(*) AS X FROM `user` WHERE (`status` = ?)", []string{"X"}, []driver.Value{int64(2)})
+	fake.returns("SELECT 1 AS X FROM `user` WHERE (`name` = ?) LIMIT 0, 1", []string{"X"})
+	fake.returns("SELECT MAX(`id`) AS X FROM `user`", []string{"X"}, []driver.Value{int64(2)})
+	fake.returns("SELECT `status`, COUNT(*) AS X FROM `user` GROUP BY `status`", []string{"status", "X"},
+		[]driver.Value{int64(1), int64(2)}, []driver.Value{int64(2), int64(1)},
+	)
+	db := fake.open()
+	defer db.Close()
+
+	ctx := context.Background()
+	repository := NewRepository(NewSqlTxManager("test", db), query.NewRDBTranslator(engine.NewMySQL()))
+	table := query.NewTable("user")
+
+	var users []*repositoryUser
+	err := repository.Execute(ctx, "FindByStatus", table, &users,
+		1, query.NewPageRequest(1, 10, false), []*query.Sort{query.NewSort("Name", query.DirectionAsc)})
+	wantUsers := []*repositoryUser{{Id: 1, Name: "Lily"}, {Id: 2, Name: "Lucy"}}
+	if err != nil || !reflect.DeepEqual(users, wantUsers) {
+		t.Errorf("Execute() find all = %v, error = %v", users, err)
+	}
+
+	var user repositoryUser
+	err = repository.Execute(ctx, "FindById", table, &user, int64(1))
+	if err != nil || user.Id != 1 || user.Name != "Lily" {
+		t.Errorf("Execute() find one = %v, error = %v", user, err)
+	}
+
+	var rowMap map[string]any
+	err = repository.Execute(ctx, "FindById", table, &rowMap, int64(1))
+	if wantMap := map[string]any{"id": int64(1), "name": "Lily"}; err != nil || !reflect.DeepEqual(rowMap, wantMap) {
+		t.Errorf("Execute() find map = %v, error = %v", rowMap, err)
+	}
+
+	var names []string
+	err = repository.Execute(ctx, "FindNameByIdIn", table, &names, []int64{1, 2})
+	if err != nil || !reflect.DeepEqual(names, []string{"Lily", "Lucy"}) {
+		t.Errorf("Execute() find names = %v, error = %v", names, err)
+	}
+
+	var total int
+	err = repository.Execute(ctx, "CountByStatus", table, &total, 1)
+	if err != nil || total != 2 {
+		t.Errorf("Execute() count = %v, error = %v", total, err)
+	}
+
+	exists := true
+	err = repository.Execute(ctx, "ExistsByName", table, &exists, "Lilei")
+	if err != nil || exists {
+		t.Errorf("Execute() exists = %v, error = %v", exists, err)
+	}
+
+	var maxId sql.NullInt64
+	err = repository.Execute(ctx, "MaxId", table, &maxId)
+	if err != nil || maxId.Int64 != 2 {
+		t.Errorf("Execute() max = %v, error = %v", maxId, err)
+	}
+
+	type statusCount struct {
+		Status int
+		X      int64
+	}
+	var counts []statusCount
+	err = repository.Execute(ctx, "CountGroupByStatus", table, &counts)
+	if wantCounts := []statusCount{{1, 2}, {2, 1}}; err != nil || !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("Execute() count groups = %v, error = %v", counts, err)
+	}
+
+	var rowsAffected int64
+	err = repository.Execute(ctx, "Save", table, &rowsAffected, &repositoryUser{Name: "Lilei"})
+	if err != nil || rowsAffected != 1 {
+		t.Errorf("Execute() save = %v, error = %v", rowsAffected, err)
+	}
+
+	if err = repository.Execute(ctx, "DeleteById", table, nil, int64(1)); err != nil {
+		t.Errorf("Execute() delete error = %v", err)
+	}
+	if err = repository.Execute(ctx, "DeleteTop5ByStatus", table, nil, 1); err != nil {
+		t.Errorf("Execute() delete top error = %v", err)
+	}
+
+	wantExecuted := []string{
+		"SELECT * FROM `user` WHERE (`status` = ?) ORDER BY `name` ASC LIMIT ?, ?",
+		"SELECT * FROM `user` WHERE (`id` = ?)",
+		"SELECT * FROM `user` WHERE (`id` = ?)",
+		"SELECT `name` FROM `user` WHERE (`id` in (?, ?))",
+		"SELECT COUNT(*) AS X FROM `user` WHERE (`status` = ?)",
+		"SELECT 1 AS X FROM `user` WHERE (`name` = ?) LIMIT 0, 1",
+		"SELECT MAX(`id`) AS X FROM `user`",
+		"SELECT `status`, COUNT(*) AS X FROM `user` GROUP BY `status`",
+		"INSERT INTO `user` (`id`, `name`, `created_at`) VALUES (?, ?, ?) " +
+			"ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `created_at` = VALUES(`created_at`)",
+		"DELETE FROM `user` WHERE (`id` = ?)",
+		"DELETE FROM `user` WHERE (`status` = ?) LIMIT ?, ?",
+	}
+	if executed := fake.log(); !reflect.DeepEqual(executed, wantExecuted) {
+		t.Errorf("executed \nactual = %v, \nexpect = %v", executed, wantExecuted)
+	}
+}
+
+func TestRepository_Execute_Error(t *testing.T) {
+	fake := newFakeDB()
+	fake.returns("SELECT * FROM `user` WHERE (`id` = ?)", []string{"id", "name"})
+	db := fake.open()
+	defer db.Close()
+
+	ctx := context.Background()
+	repository := NewRepository(NewSqlTxManager("test", db), query.NewRDBTranslator(engine.NewMySQL()))
+	table := query.NewTable("user")
+
+	var user repositoryUser
+	if err := repository.Execute(ctx, "FindById", table, &user, int64(1)); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Execute() error = %v, want %v", err, sql.ErrNoRows)
+	}
+	if err := repository.Execute(ctx, "LoadById", table, &user, int64(1)); err == nil {
+		t.Errorf("Execute() expected error for the unparsable method")
+	}
+	if err := repository.Execute(ctx, "FindById", table, &user); err == nil {
+		t.Errorf("Execute() expected error for the missing values")
+	}
+	if err := repository.Execute(ctx, "FindById", table, user, int64(1)); err == nil {
+		t.Errorf("Execute() expected error for the non-pointer dest")
+	}
+	if err := repository.Execute(ctx, "CountById", table, nil, int64(1)); err == nil {
+		t.Errorf("Execute() expected error for the missing dest")
+	}
+	if err := repository.Execute(ctx, "CountById", table, new(int64), int64(1),
+		query.NewPageRequest(1, 10, false)); err == nil {
+		t.Errorf("Execute() expected error for the pager of count")
+	}
+	if err := repository.Execute(ctx, "CountGroupByStatus", table, new(int64)); err == nil {
+		t.Errorf("Execute() expected error for the non-slice dest of the grouped count")
+	}
+
+	sortedRepository := NewRepository(NewSqlTxManager("test", db), query.NewRDBTranslator(engine.NewMySQL()),
+		WithRepositorySortFields("Name"))
+	var users []repositoryUser
+	if err := sortedRepository.Execute(ctx, "FindByStatus", table, &users, 1,
+		[]*query.Sort{query.NewSort("Password", query.DirectionAsc)}); err == nil {
+		t.Errorf("Execute() expected error for the sort field not allowed")
+	}
+
+	q1, _ := repository.Parse("FindById")
+	q2, _ := repository.Parse("FindById")
+	if q1 != q2 {
+		t.Errorf("Parse() expected the cached query")
+	}
+}
@@ -2,32 +2,45 @@ package data
 
 import (
 	"fmt"
+	"github.com/gomelon/melon"
 	"github.com/gomelon/melon/data/query"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 const (
 	keywordBy      = "By"
+	keywordGroupBy = "GroupBy"
 	keywordOrderBy = "OrderBy"
 )
 
 //RuleParser
-//Format:     $Subject [$SubjectModifier] [@Filter] [$Sort]
+//Format:     $Subject [$SubjectModifier] [$Projection] [@Filter] [$GroupBy] [$Sort]
 //
 //Subject:
 //    Find Query Get Search:General query method returning typically the repository type,slice or struct
 //    Count                : Count projection returning a numeric result.
 //    Exists               : Exists projection, returning typically a boolean result.
 //    Delete Remove        : Delete query method returning either no result (void) or the delete count.
+//    Update               : Update the fields, Format: Update$Field[And $Field], EX: UpdateStatusById,
+//                           the args of the fields precede the args of the filters
+//    Insert Save          : Insert the rows of the entity, Save updates the conflicting rows instead,
+//                           the method is named by the subject only, the fields are derived from the entity
+//    Sum Avg Min Max      : Aggregate the target field, Format: $Subject$Field, EX: SumAmountByUserId
+//                           the sum of the empty set is 0, the others are NULL
 //
 //SubjectModifier:
 //    Distinct:    Use a distinct query to return only unique results.
 //    Top<Number>: Limit the query results to the first <number> of results.
 //
+//Projection: $Field[And $Field], only for Find, select the fields only, EX: FindNameAndEmailById
+//            All One First are reserved, they select all the fields, EX: FindAllByStatus FindOneById
+//
 //Filter:  By$Field$Predicate[$FilterModifier][And|Or $Field$Predicate[$FilterModifier]]
-//    Remark: If you want to support nested fields later, use _ to separate the nesting
+//    Remark: Use _ to separate the nesting of the nested fields, EX: ByAddress_City,
+//            the translator decides the column of the nested field by NestedFieldMode
 //Predicate:
 //    Is, Equals, (or no keyword)
 //    Contains: for string contains substring or collection contains an element
@@ -43,6 +56,11 @@ const (
 //FilterModifier:
 //    IgnoreCase:    Used with a predicate keyword for case-insensitive comparison.
 //    AllIgnoreCase: Ignore case for all suitable properties. Used somewhere in the query method predicate.
+//                   The suitable properties are filtered by Is IsNot Contains StartsWith EndsWith In NotIn
+//
+//GroupBy:    GroupBy$Field[And $Field], the grouping fields are selected before the aggregate value,
+//            the grouped query can be sorted by the fields or the aggregate value named by the subject,
+//            EX: CountGroupByStatusOrderByCountDesc
 //
 //Sort:       Specify a static sorting order followed by the field path and direction
 //            Format: OrderBy$Field[$Direction], EX: OrderByFirstnameAscLastnameDesc
@@ -76,15 +94,58 @@ func (r *RuleParser) Parse(method string) (q *query.Query, err error) {
 	}
 
 	remaining = remaining[nextIndex:]
+	if subject.Insertable() {
+		if len(remaining) > 0 {
+			err = fmt.Errorf("method rule parse fail: can not parse [%s], subject [%s] must be used alone",
+				remaining, subject.Name())
+			return
+		}
+		q = query.New(subject)
+		return
+	}
+
+	var projection *query.Projection
+	if subject.Projectable() {
+		projection, nextIndex, err = r.parseProjection(remaining)
+		if err != nil {
+			return
+		}
+		remaining = remaining[nextIndex:]
+	}
+
+	var assignments []*query.Assignment
+	if subject.Assignable() {
+		assignments, nextIndex, err = r.parseAssignments(subject, remaining)
+		if err != nil {
+			return
+		}
+		remaining = remaining[nextIndex:]
+	}
+
+	var aggregateField string
+	if subject.IsAggregate() {
+		aggregateField, nextIndex, err = r.parseAggregateField(subject, remaining)
+		if err != nil {
+			return
+		}
+		remaining = remaining[nextIndex:]
+	}
+
 	filterGroup, nextIndex, err := r.parseFilters(remaining)
 	if err != nil {
 		return
 	}
+	if filterGroup != nil {
+		filterGroup.PropagateIgnoreCase()
+	}
 
 	remaining = remaining[nextIndex:]
+	groupBy, nextIndex := r.parseGroupBy(remaining)
 
+	remaining = remaining[nextIndex:]
+	nextIndex = 0
 	var sorts []*query.Sort
-	if subject.Sortable() {
+	if subject.Sortable() || len(groupBy) > 0 {
 		sorts, nextIndex, err = r.parseSort(remaining)
 		if err != nil {
 			return
@@ -96,11 +157,20 @@ func (r *RuleParser) Parse(method string) (q *query.Query, err error) {
 		return
 	}
 	q = query.New(subject, query.WithSubjectModifier(subjectModifier), subjectModifierArgsOpt,
-		query.WithFilterGroup(filterGroup), query.WithSorts(sorts),
+		query.WithProjection(projection), query.WithAggregateField(aggregateField),
+		query.WithAssignments(assignments), query.WithFilterGroup(filterGroup), query.WithGroupBy(groupBy), query.WithSorts(sorts),
 	)
 	return
 }
 
+// MustParse is like Parse but panics if the method can not be parsed, the options are applied to the query,
+// it is used by the generated repositories whose methods have been parsed on generating
+func (r *RuleParser) MustParse(method string, opts ...query.Option) *query.Query {
+	q, err := r.Parse(method)
+	melon.PanicOnError(err)
+	return q.With(opts...)
+}
+
 func (r *RuleParser) ParseSubject(method string) (*query.Subject, error) {
 	subject, _, err := r.parseSubject(method)
 	return subject, err
@@ -152,7 +222,7 @@ func (r *RuleParser) parseSubjectModifierArg(modifier *query.SubjectModifier, st
 	case query.SubjectModifierTop:
 		topN := -1
 		topLen := 0
-		for i := nextIndex + 1; i < len(str); i++ {
+		for i := nextIndex + 1; i <= len(str); i++ {
 			s := str[nextIndex:i]
 			n, err := strconv.Atoi(s)
 			if err != nil {
@@ -174,19 +244,75 @@ func (r *RuleParser) parseSubjectModifierArg(modifier *query.SubjectModifier, st
 	return
 }
 
+// reservedProjections are not the fields to select, EX: FindAllByStatus selects all the fields
+var reservedProjections = map[string]bool{"All": true, "One": true, "First": true}
+
+func (r *RuleParser) parseProjection(str string) (projection *query.Projection, nextIndex int, err error) {
+	nextIndex = r.indexOfFilters(str)
+	if nextIndex == 0 || reservedProjections[str[:nextIndex]] {
+		return
+	}
+	fieldNames := r.splitByAndKeyword(str[:nextIndex])
+	for _, fieldName := range fieldNames {
+		if len(fieldName) == 0 || !unicode.IsUpper(rune(fieldName[0])) {
+			err = fmt.Errorf("method rule parse fail: [%s] projection field [%s] is invalid, "+
+				"field must starts with an upper case letter", str, fieldName)
+			return
+		}
+	}
+	projection = query.NewProjection(fieldNames...)
+	return
+}
+
+func (r *RuleParser) parseAssignments(subject *query.Subject, str string,
+) (assignments []*query.Assignment, nextIndex int, err error) {
+
+	nextIndex = r.indexOfFilters(str)
+	if nextIndex == 0 {
+		err = fmt.Errorf("method rule parse fail: [%s] must follow the fields to set, EX: %sStatusById",
+			str, subject.Name())
+		return
+	}
+	assignments = query.NewAssignments(r.splitByAndKeyword(str[:nextIndex])...)
+	return
+}
+
+func (r *RuleParser) parseAggregateField(subject *query.Subject, str string,
+) (fieldName string, nextIndex int, err error) {
+
+	nextIndex = r.indexOfFilters(str)
+	if nextIndex == 0 {
+		err = fmt.Errorf("method rule parse fail: [%s] must follow the field to aggregate, EX: %sAmountByUserId",
+			str, subject.Name())
+		return
+	}
+	fieldName = str[:nextIndex]
+	return
+}
+
+// indexOfFilters the index of the By, GroupBy or OrderBy which ends the fields after the subject
+func (r *RuleParser) indexOfFilters(str string) (index int) {
+	index = len(str)
+	for _, keyword := range []string{keywordBy, keywordGroupBy, keywordOrderBy} {
+		if keywordIndex := strings.Index(str, keyword); keywordIndex >= 0 && keywordIndex < index {
+			index = keywordIndex
+		}
+	}
+	return
+}
+
 func (r *RuleParser) parseFilters(str string) (group *query.FilterGroup, nextIndex int, err error) {
 	if !strings.HasPrefix(str, keywordBy) {
 		return
 	}
-	orderByIndex := strings.Index(str, keywordOrderBy)
-	var filtersStr string
-	if orderByIndex > 0 {
+	nextIndex = len(str)
+	if groupByIndex := strings.Index(str, keywordGroupBy); groupByIndex > 0 {
+		nextIndex = groupByIndex
+	}
+	if orderByIndex := strings.Index(str, keywordOrderBy); orderByIndex > 0 && orderByIndex < nextIndex {
 		nextIndex = orderByIndex
-		filtersStr = str[len(keywordBy):orderByIndex]
-	} else {
-		nextIndex = len(str)
-		filtersStr = str[len(keywordBy):]
 	}
+	filtersStr := str[len(keywordBy):nextIndex]
 
 	orParts := r.splitByOrKeyword(filtersStr)
 	if len(orParts) == 1 {
@@ -201,6 +327,18 @@ func (r *RuleParser) parseFilters(str string) (group *query.FilterGroup, nextInd
 	return
 }
 
+func (r *RuleParser) parseGroupBy(str string) (fieldNames []string, nextIndex int) {
+	if !strings.HasPrefix(str, keywordGroupBy) {
+		return
+	}
+	nextIndex = len(str)
+	if orderByIndex := strings.Index(str, keywordOrderBy); orderByIndex > 0 {
+		nextIndex = orderByIndex
+	}
+	fieldNames = r.splitByAndKeyword(str[len(keywordGroupBy):nextIndex])
+	return
+}
+
 func (r *RuleParser) parseSort(str string) (sorts []*query.Sort, nextIndex int, err error) {
 	if !strings.HasPrefix(str, keywordOrderBy) {
 		return
@@ -1,7 +1,9 @@
 package data
 
 import (
+	"context"
 	"fmt"
+	"github.com/gomelon/melon/data/engine"
 	"github.com/gomelon/melon/data/query"
 	"reflect"
 	"testing"
@@ -187,6 +189,132 @@ func TestRuleParser_Parse_Find(t *testing.T) {
 			),
 			wantErr: false,
 		},
+		{
+			name:       "find top",
+			methodName: "FindTop10",
+			wantQuery: query.New(
+				query.SubjectFind,
+				query.WithSubjectModifier(query.SubjectModifierTop),
+				query.WithPager(query.NewPageRequest(1, 10, false)),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "find top invalid projection",
+			methodName: "FindTop1xNameById",
+			wantErr:    true,
+		},
+		{
+			name:       "find ignore case",
+			methodName: "FindByNameIgnoreCaseAndAgeGT",
+			wantQuery: query.New(
+				query.SubjectFind,
+				query.WithFilterGroup(
+					query.NewFilterGroupWithFilters([]*query.Filter{
+						query.NewFilter("Name", query.PredicateIs,
+							query.WithFilterModifier(query.FilterModifierIgnoreCase)),
+						query.NewFilter("Age", query.PredicateGT),
+					}, query.LogicOperatorAnd),
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "find all ignore case",
+			methodName: "FindByNameContainsOrEmailAndAgeGTAllIgnoreCase",
+			wantQuery: query.New(
+				query.SubjectFind,
+				query.WithFilterGroup(
+					query.NewFilterGroup([]*query.FilterGroup{
+						query.NewFilterGroupWithFilters([]*query.Filter{
+							query.NewFilter("Name", query.PredicateContains,
+								query.WithFilterModifier(query.FilterModifierAllIgnoreCase)),
+						}, query.LogicOperatorAnd),
+						query.NewFilterGroupWithFilters([]*query.Filter{
+							query.NewFilter("Email", query.PredicateIs,
+								query.WithFilterModifier(query.FilterModifierAllIgnoreCase)),
+							query.NewFilter("Age", query.PredicateGT),
+						}, query.LogicOperatorAnd),
+					}, query.LogicOperatorOr),
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "find all ignore case short keyword",
+			methodName: "FindByNameAllIC",
+			wantQuery: query.New(
+				query.SubjectFind,
+				query.WithFilterGroup(
+					query.NewFilterGroupWithFilters([]*query.Filter{
+						query.NewFilter("Name", query.PredicateIs,
+							query.WithFilterModifier(query.FilterModifierAllIgnoreCase)),
+					}, query.LogicOperatorAnd),
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "find projection filter",
+			methodName: "FindNameAndEmailById",
+			wantQuery: query.New(
+				query.SubjectFind,
+				query.WithProjection(query.NewProjection("Name", "Email")),
+				query.WithFilterGroup(
+					query.NewFilterGroupWithFilters([]*query.Filter{
+						query.NewFilter("Id", query.PredicateIs),
+					}, query.LogicOperatorAnd),
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "find all",
+			methodName: "FindAll",
+			wantQuery:  query.New(query.SubjectFind),
+			wantErr:    false,
+		},
+		{
+			name:       "find all filter",
+			methodName: "FindAllByStatus",
+			wantQuery: query.New(
+				query.SubjectFind,
+				query.WithFilterGroup(
+					query.NewFilterGroupWithFilters([]*query.Filter{
+						query.NewFilter("Status", query.PredicateIs),
+					}, query.LogicOperatorAnd),
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "find one filter",
+			methodName: "FindOneById",
+			wantQuery: query.New(
+				query.SubjectFind,
+				query.WithFilterGroup(
+					query.NewFilterGroupWithFilters([]*query.Filter{
+						query.NewFilter("Id", query.PredicateIs),
+					}, query.LogicOperatorAnd),
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "find distinct projection order by",
+			methodName: "FindDistinctNameOrderByName",
+			wantQuery: query.New(
+				query.SubjectFind,
+				query.WithSubjectModifier(query.SubjectModifierDistinct),
+				query.WithProjection(query.NewProjection("Name")),
+				query.WithSorts(
+					[]*query.Sort{
+						query.NewSort("Name", query.DirectionAsc),
+					},
+				),
+			),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -209,6 +337,37 @@ func TestRuleParser_Parse_Find(t *testing.T) {
 	}
 }
 
+func TestRuleParser_Parse_AllIgnoreCaseMixedTypes(t *testing.T) {
+	q, err := NewRuleParser().MustParse("FindByNameAndAgeAllIgnoreCase",
+		query.WithTable(query.NewTable("user"))).Bind("Lily", 18)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statement, err := query.NewRDBTranslator(engine.NewPostgreSQL()).Translate(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSQL := `SELECT * FROM "user" WHERE ((LOWER("name") = LOWER($1)) AND ("age" = $2))`
+	if statement.SQL() != wantSQL {
+		t.Errorf("Translate() \nactual = %v, \nexpect = %v", statement.SQL(), wantSQL)
+	}
+}
+
+func TestRuleParser_Parse_AllIgnoreCaseNamedArgs(t *testing.T) {
+	q := NewRuleParser().MustParse("FindByNameOrEmailContainsAllIgnoreCase", query.WithTable(query.NewTable("user")))
+	if err := q.FilterGroup().FillNamedArgs([]string{"name", "email"}); err != nil {
+		t.Fatal(err)
+	}
+	statement, err := query.NewRDBTranslator(engine.NewPostgreSQL()).Translate(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSQL := `SELECT * FROM "user" WHERE ((LOWER("name") = LOWER($1)) OR ("email" ILIKE '%' || $2 || '%'))`
+	if statement.SQL() != wantSQL {
+		t.Errorf("Translate() \nactual = %v, \nexpect = %v", statement.SQL(), wantSQL)
+	}
+}
+
 func TestRuleParser_Parse_Count(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -266,6 +425,34 @@ func TestRuleParser_Parse_Count(t *testing.T) {
 			),
 			wantErr: false,
 		},
+		{
+			name:       "count group by order by",
+			methodName: "CountByAgeGTGroupByStatusAndTypeOrderByCountDesc",
+			wantQuery: query.New(
+				query.SubjectCount,
+				query.WithFilterGroup(
+					query.NewFilterGroupWithFilters([]*query.Filter{
+						query.NewFilter("Age", query.PredicateGT),
+					}, query.LogicOperatorAnd),
+				),
+				query.WithGroupBy([]string{"Status", "Type"}),
+				query.WithSorts(
+					[]*query.Sort{
+						query.NewSort("Count", query.DirectionDesc),
+					},
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "count group by without filter",
+			methodName: "CountGroupByStatus",
+			wantQuery: query.New(
+				query.SubjectCount,
+				query.WithGroupBy([]string{"Status"}),
+			),
+			wantErr: false,
+		},
 		{
 			name:       "filter count and order by",
 			methodName: "CountByIdOrderByFirstname",
@@ -294,6 +481,184 @@ func TestRuleParser_Parse_Count(t *testing.T) {
 	}
 }
 
+func TestRuleParser_Parse_Aggregate(t *testing.T) {
+	tests := []struct {
+		name       string
+		methodName string
+		wantQuery  *query.Query
+		wantErr    bool
+	}{
+		{
+			name:       "simple sum",
+			methodName: "SumAmountByUserId",
+			wantQuery: query.New(
+				query.SubjectSum,
+				query.WithAggregateField("Amount"),
+				query.WithFilterGroup(
+					query.NewFilterGroupWithFilters([]*query.Filter{
+						query.NewFilter("UserId", query.PredicateIs),
+					}, query.LogicOperatorAnd),
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "max without filter",
+			methodName: "MaxCreatedAt",
+			wantQuery: query.New(
+				query.SubjectMax,
+				query.WithAggregateField("CreatedAt"),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "average with predicate",
+			methodName: "AverageScoreByAgeGTE",
+			wantQuery: query.New(
+				query.SubjectAvg,
+				query.WithAggregateField("Score"),
+				query.WithFilterGroup(
+					query.NewFilterGroupWithFilters([]*query.Filter{
+						query.NewFilter("Age", query.PredicateGTE),
+					}, query.LogicOperatorAnd),
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "sum group by",
+			methodName: "SumAmountGroupByUserId",
+			wantQuery: query.New(
+				query.SubjectSum,
+				query.WithAggregateField("Amount"),
+				query.WithGroupBy([]string{"UserId"}),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "aggregate without field",
+			methodName: "MinByStatus",
+			wantQuery:  nil,
+			wantErr:    true,
+		},
+		{
+			name:       "aggregate order by",
+			methodName: "SumAmountByUserIdOrderByAmount",
+			wantQuery:  nil,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRuleParser()
+			gotQuery, err := r.Parse(tt.methodName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotQuery, tt.wantQuery) {
+				t.Errorf("Parse() actual = %v, expect = %v", gotQuery, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestRuleParser_Parse_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		methodName string
+		wantQuery  *query.Query
+		wantErr    bool
+	}{
+		{
+			name:       "simple update",
+			methodName: "UpdateStatusById",
+			wantQuery: query.New(
+				query.SubjectUpdate,
+				query.WithAssignments(query.NewAssignments("Status")),
+				query.WithFilterGroup(
+					query.NewFilterGroupWithFilters([]*query.Filter{
+						query.NewFilter("Id", query.PredicateIs),
+					}, query.LogicOperatorAnd),
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "update multiple fields",
+			methodName: "UpdateNameAndEmailByIdIn",
+			wantQuery: query.New(
+				query.SubjectUpdate,
+				query.WithAssignments(query.NewAssignments("Name", "Email")),
+				query.WithFilterGroup(
+					query.NewFilterGroupWithFilters([]*query.Filter{
+						query.NewFilter("Id", query.PredicateIn),
+					}, query.LogicOperatorAnd),
+				),
+			),
+			wantErr: false,
+		},
+		{
+			name:       "simple insert",
+			methodName: "Insert",
+			wantQuery:  query.New(query.SubjectInsert),
+			wantErr:    false,
+		},
+		{
+			name:       "simple save",
+			methodName: "Save",
+			wantQuery:  query.New(query.SubjectSave),
+			wantErr:    false,
+		},
+		{
+			name:       "insert with filter",
+			methodName: "InsertById",
+			wantQuery:  nil,
+			wantErr:    true,
+		},
+		{
+			name:       "update without field",
+			methodName: "UpdateById",
+			wantQuery:  nil,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRuleParser()
+			gotQuery, err := r.Parse(tt.methodName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotQuery, tt.wantQuery) {
+				t.Errorf("Parse() actual = %v, expect = %v", gotQuery, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestRuleParser_Parse_NestedField(t *testing.T) {
+	r := NewRuleParser()
+	gotQuery, err := r.Parse("FindByAddress_CityAndAddress_ZipOrderByAddress_City")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	filters := gotQuery.FilterGroup().Filters()
+	wantPaths := []query.FieldPath{{"Address", "City"}, {"Address", "Zip"}}
+	if len(filters) != len(wantPaths) {
+		t.Fatalf("Parse() actual %d filters, expect %d filters", len(filters), len(wantPaths))
+	}
+	for i, filter := range filters {
+		if !reflect.DeepEqual(filter.FieldPath(), wantPaths[i]) {
+			t.Errorf("Parse() actual path = %v, expect path = %v", filter.FieldPath(), wantPaths[i])
+		}
+	}
+	if sortPath := gotQuery.Sorts()[0].FieldPath(); !reflect.DeepEqual(sortPath, query.FieldPath{"Address", "City"}) {
+		t.Errorf("Parse() actual sort path = %v, expect sort path = %v", sortPath, wantPaths[0])
+	}
+}
+
 func TestRuleParser_splitByOrKeyword(t *testing.T) {
 	type args struct {
 		str string
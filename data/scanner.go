@@ -0,0 +1,256 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/gomelon/melon/data/engine"
+	"github.com/huandu/xstrings"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+
+	defaultScanner = NewScanner(nil)
+)
+
+// NewRowMapper returns the mapper of T scanned by the default scanner, see Scanner
+func NewRowMapper[T any]() RowMapper[T] {
+	return NewScannerRowMapper[T](defaultScanner)
+}
+
+// NewScannerRowMapper returns the mapper of T scanned by the scanner
+func NewScannerRowMapper[T any](scanner *Scanner) RowMapper[T] {
+	mapper := scanner.valueMapper(reflect.TypeOf((*T)(nil)).Elem())
+	return func(rows *sql.Rows) (value T, err error) {
+		mappedValue, err := mapper(rows)
+		if err != nil {
+			return
+		}
+		value, _ = mappedValue.Interface().(T)
+		return
+	}
+}
+
+// Scanner maps the columns of the rows to the values.
+// The struct or pointer to struct is mapped column by column, the column of the field is the db tag,
+// or the column built by Engine.BuildColumn from the field name, and matched ignoring case if not exactly.
+// The fields of the embedded structs are promoted, the pointer fields are allocated,
+// time.Time, sql.Null* and other sql.Scanner are scanned as a whole, the columns without field are discarded.
+// map[string]any is mapped by the column names, the other types are scanned from the only column.
+// The field plans are cached per struct type.
+type Scanner struct {
+	engin engine.Engine
+	plans sync.Map
+}
+
+// NewScanner the columns of the fields are built by the engine, or snake_case if the engine is nil
+func NewScanner(engin engine.Engine) *Scanner {
+	return &Scanner{engin: engin}
+}
+
+// Scan scans the current row to the dest, the dest is a non-nil pointer
+func (s *Scanner) Scan(rows *sql.Rows, dest any) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.IsNil() {
+		return fmt.Errorf("scan fail: the dest must be a non-nil pointer, but actual %T", dest)
+	}
+	value, err := s.valueMapper(destValue.Type().Elem())(rows)
+	if err != nil {
+		return err
+	}
+	destValue.Elem().Set(value)
+	return nil
+}
+
+// ScanAll scans the remaining rows to the dest, the dest is a non-nil pointer to slice, the rows are not closed
+func (s *Scanner) ScanAll(rows *sql.Rows, dest any) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Pointer || destValue.IsNil() || destValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("scan fail: the dest must be a non-nil pointer to slice, but actual %T", dest)
+	}
+	sliceValue := destValue.Elem()
+	mapper := s.valueMapper(sliceValue.Type().Elem())
+	for rows.Next() {
+		value, err := mapper(rows)
+		if err != nil {
+			return err
+		}
+		sliceValue = reflect.Append(sliceValue, value)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	destValue.Elem().Set(sliceValue)
+	return nil
+}
+
+// valueMapper returns the mapper of the value type, the mapped value is of the value type
+func (s *Scanner) valueMapper(valueType reflect.Type) func(rows *sql.Rows) (reflect.Value, error) {
+	if valueType.Kind() == reflect.Map && valueType.Key().Kind() == reflect.String {
+		return func(rows *sql.Rows) (reflect.Value, error) {
+			return scanMap(rows, valueType)
+		}
+	}
+	structType := valueType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if !isStructPlanned(structType) {
+		return func(rows *sql.Rows) (reflect.Value, error) {
+			value := reflect.New(valueType)
+			if err := rows.Scan(value.Interface()); err != nil {
+				return reflect.Value{}, err
+			}
+			return value.Elem(), nil
+		}
+	}
+
+	plan := s.planOf(structType)
+	return func(rows *sql.Rows) (reflect.Value, error) {
+		columns, err := rows.Columns()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		structValue := reflect.New(structType)
+		dest := make([]any, 0, len(columns))
+		for _, column := range columns {
+			index, ok := plan.indexOf(column)
+			if !ok {
+				dest = append(dest, new(any))
+				continue
+			}
+			dest = append(dest, fieldByIndexAlloc(structValue.Elem(), index).Addr().Interface())
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return reflect.Value{}, fmt.Errorf("scan row to %v fail: %w", structType, err)
+		}
+		if valueType.Kind() == reflect.Pointer {
+			return structValue, nil
+		}
+		return structValue.Elem(), nil
+	}
+}
+
+// isStructPlanned the struct is mapped field by field, except time.Time and sql.Scanner
+func isStructPlanned(structType reflect.Type) bool {
+	return structType.Kind() == reflect.Struct && structType != timeType &&
+		!reflect.PointerTo(structType).Implements(scannerType)
+}
+
+// scanPlan is the index sequences of the fields by their columns
+type scanPlan struct {
+	fields      map[string][]int
+	lowerFields map[string][]int
+}
+
+func (p *scanPlan) indexOf(column string) (index []int, ok bool) {
+	if index, ok = p.fields[column]; ok {
+		return
+	}
+	index, ok = p.lowerFields[strings.ToLower(column)]
+	return
+}
+
+func (s *Scanner) planOf(structType reflect.Type) *scanPlan {
+	if plan, ok := s.plans.Load(structType); ok {
+		return plan.(*scanPlan)
+	}
+	plan := &scanPlan{fields: map[string][]int{}, lowerFields: map[string][]int{}}
+	s.planFields(plan, structType, nil)
+	actual, _ := s.plans.LoadOrStore(structType, plan)
+	return actual.(*scanPlan)
+}
+
+// planFields the fields of the outer struct shadow the fields of the embedded structs with the same column
+func (s *Scanner) planFields(plan *scanPlan, structType reflect.Type, parentIndex []int) {
+	var embedded []reflect.StructField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && len(tag) == 0 && isStructPlanned(fieldType) {
+			//the pointer of the unexported struct can not be allocated
+			if field.IsExported() || field.Type.Kind() != reflect.Pointer {
+				embedded = append(embedded, field)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		column := tag
+		if len(column) == 0 {
+			column = s.buildColumn(field.Name)
+		}
+		index := append(append(make([]int, 0, len(parentIndex)+1), parentIndex...), i)
+		if _, ok := plan.fields[column]; !ok {
+			plan.fields[column] = index
+		}
+		if _, ok := plan.lowerFields[strings.ToLower(column)]; !ok {
+			plan.lowerFields[strings.ToLower(column)] = index
+		}
+	}
+	for _, field := range embedded {
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		index := append(append(make([]int, 0, len(parentIndex)+1), parentIndex...), field.Index...)
+		s.planFields(plan, fieldType, index)
+	}
+}
+
+func (s *Scanner) buildColumn(fieldName string) string {
+	if s.engin == nil {
+		return xstrings.ToSnakeCase(fieldName)
+	}
+	return s.engin.BuildColumn(fieldName)
+}
+
+// scanMap scans the row to the map keyed by the column names
+func scanMap(rows *sql.Rows, mapType reflect.Type) (reflect.Value, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	values := make([]reflect.Value, 0, len(columns))
+	dest := make([]any, 0, len(columns))
+	for range columns {
+		value := reflect.New(mapType.Elem())
+		values = append(values, value)
+		dest = append(dest, value.Interface())
+	}
+	if err = rows.Scan(dest...); err != nil {
+		return reflect.Value{}, fmt.Errorf("scan row to %v fail: %w", mapType, err)
+	}
+	mapValue := reflect.MakeMapWithSize(mapType, len(columns))
+	for i, column := range columns {
+		mapValue.SetMapIndex(reflect.ValueOf(column).Convert(mapType.Key()), values[i].Elem())
+	}
+	return mapValue, nil
+}
+
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex, but allocates the nil pointers of embedded structs
+func fieldByIndexAlloc(value reflect.Value, index []int) reflect.Value {
+	for i, fieldIndex := range index {
+		if i > 0 && value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(fieldIndex)
+	}
+	return value
+}
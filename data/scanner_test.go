@@ -0,0 +1,235 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"github.com/gomelon/melon/data/engine"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type scannerStatus string
+
+func (s *scannerStatus) Scan(src any) error {
+	value, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("scan status fail: unexpected %T", src)
+	}
+	*s = scannerStatus(strings.ToUpper(value))
+	return nil
+}
+
+type ScannerAudit struct {
+	CreatedAt time.Time
+	UpdatedBy *string
+}
+
+type scannerUserBase struct {
+	Id int64 `db:"id"`
+}
+
+type scannerAccount struct {
+	scannerUserBase
+	Name      string
+	CreatedAt string `db:"created_at"`
+	Password  string `db:"-"`
+}
+
+type scannerUser struct {
+	*ScannerAudit
+	scannerUserBase
+	UserName string
+	Nickname sql.NullString
+	Status   scannerStatus `db:"state"`
+	Age      *int
+}
+
+func TestNewRowMapper(t *testing.T) {
+	fake := newFakeDB()
+	fake.returns("SELECT users", []string{"id", "name", "created_at", "password", "unknown"},
+		[]driver.Value{int64(1), "Lily", "2022-10-01", "secret", "x"},
+	)
+	fake.returns("SELECT names", []string{"name"}, []driver.Value{"Lily"}, []driver.Value{"Lucy"})
+	db := fake.open()
+	defer db.Close()
+
+	ctx := context.Background()
+	rows, err := db.QueryContext(ctx, "SELECT users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Next()
+	user, err := NewRowMapper[*scannerAccount]()(rows)
+	_ = rows.Close()
+	if err != nil {
+		t.Errorf("NewRowMapper() error = %v", err)
+		return
+	}
+	wantUser := &scannerAccount{scannerUserBase: scannerUserBase{Id: 1}, Name: "Lily", CreatedAt: "2022-10-01"}
+	if !reflect.DeepEqual(user, wantUser) {
+		t.Errorf("NewRowMapper() \nactual = %#v, \nexpect = %#v", user, wantUser)
+	}
+
+	rows, err = db.QueryContext(ctx, "SELECT names")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var names []string
+	mapper := NewRowMapper[string]()
+	for rows.Next() {
+		name, err := mapper(rows)
+		if err != nil {
+			t.Errorf("NewRowMapper() error = %v", err)
+			return
+		}
+		names = append(names, name)
+	}
+	if !reflect.DeepEqual(names, []string{"Lily", "Lucy"}) {
+		t.Errorf("NewRowMapper() \nactual = %v, \nexpect = %v", names, []string{"Lily", "Lucy"})
+	}
+}
+
+func TestScanner_Scan(t *testing.T) {
+	createdAt := time.Date(2022, 10, 1, 8, 0, 0, 0, time.UTC)
+	age := 18
+	updatedBy := "admin"
+	tests := []struct {
+		name    string
+		engin   engine.Engine
+		columns []string
+		row     []driver.Value
+		want    *scannerUser
+		wantErr bool
+	}{
+		{
+			name:    "default",
+			columns: []string{"id", "user_name", "nickname", "state", "age", "created_at", "updated_by", "unknown"},
+			row:     []driver.Value{int64(1), "Lily", nil, "active", int64(18), createdAt, "admin", "x"},
+			want: &scannerUser{
+				ScannerAudit:    &ScannerAudit{CreatedAt: createdAt, UpdatedBy: &updatedBy},
+				scannerUserBase: scannerUserBase{Id: 1},
+				UserName:        "Lily",
+				Status:          "ACTIVE",
+				Age:             &age,
+			},
+		},
+		{
+			name:    "uppercase columns",
+			engin:   engine.NewOracle(),
+			columns: []string{"ID", "USER_NAME", "NICKNAME", "STATE", "AGE"},
+			row:     []driver.Value{int64(1), "Lily", "lily", "active", nil},
+			want: &scannerUser{
+				scannerUserBase: scannerUserBase{Id: 1},
+				UserName:        "Lily",
+				Nickname:        sql.NullString{String: "lily", Valid: true},
+				Status:          "ACTIVE",
+			},
+		},
+		{
+			name:    "unscannable",
+			columns: []string{"state"},
+			row:     []driver.Value{int64(1)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeDB()
+			fake.returns("SELECT users", tt.columns, tt.row)
+			db := fake.open()
+			defer db.Close()
+			rows, err := db.QueryContext(context.Background(), "SELECT users")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+			rows.Next()
+
+			got := &scannerUser{}
+			err = NewScanner(tt.engin).Scan(rows, got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() \nactual = %#v, \nexpect = %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanner_ScanAll(t *testing.T) {
+	fake := newFakeDB()
+	fake.returns("SELECT users", []string{"id", "name"},
+		[]driver.Value{int64(1), "Lily"}, []driver.Value{int64(2), "Lucy"})
+	fake.returns("SELECT times", []string{"created_at"}, []driver.Value{time.Unix(0, 0).UTC()})
+	db := fake.open()
+	defer db.Close()
+	ctx := context.Background()
+	scanner := NewScanner(engine.NewMySQL())
+
+	rows, err := db.QueryContext(ctx, "SELECT users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var users []scannerAccount
+	err = scanner.ScanAll(rows, &users)
+	_ = rows.Close()
+	if err != nil {
+		t.Errorf("ScanAll() error = %v", err)
+		return
+	}
+	wantUsers := []scannerAccount{
+		{scannerUserBase: scannerUserBase{Id: 1}, Name: "Lily"},
+		{scannerUserBase: scannerUserBase{Id: 2}, Name: "Lucy"},
+	}
+	if !reflect.DeepEqual(users, wantUsers) {
+		t.Errorf("ScanAll() \nactual = %#v, \nexpect = %#v", users, wantUsers)
+	}
+
+	rows, err = db.QueryContext(ctx, "SELECT times")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var times []time.Time
+	if err = scanner.ScanAll(rows, &times); err != nil {
+		t.Errorf("ScanAll() error = %v", err)
+		return
+	}
+	if len(times) != 1 || !times[0].Equal(time.Unix(0, 0)) {
+		t.Errorf("ScanAll() \nactual = %v, \nexpect = %v", times, time.Unix(0, 0))
+	}
+
+	if err = scanner.ScanAll(rows, users); err == nil {
+		t.Errorf("ScanAll() expected error for the non-pointer dest")
+	}
+}
+
+func TestScanner_planOf(t *testing.T) {
+	scanner := NewScanner(engine.NewMySQL())
+	plan := scanner.planOf(reflect.TypeOf(scannerUser{}))
+	if cached := scanner.planOf(reflect.TypeOf(scannerUser{})); cached != plan {
+		t.Errorf("planOf() expected the cached plan")
+	}
+	wantFields := map[string][]int{
+		"id":         {1, 0},
+		"user_name":  {2},
+		"nickname":   {3},
+		"state":      {4},
+		"age":        {5},
+		"created_at": {0, 0},
+		"updated_by": {0, 1},
+	}
+	if !reflect.DeepEqual(plan.fields, wantFields) {
+		t.Errorf("planOf() \nactual = %v, \nexpect = %v", plan.fields, wantFields)
+	}
+}
@@ -3,16 +3,114 @@ package data
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 )
 
 type TXName string
 
+// ErrTXRollbackOnly is returned by CommitScope when the transaction was marked rollback-only by a participating scope,
+// the transaction is rolled back instead
+var ErrTXRollbackOnly = errors.New("transaction is marked as rollback-only")
+
+// Propagation decides how Begin behaves when the context is or is not bound with a transaction
+type Propagation int
+
+const (
+	// PropagationRequired joins the current transaction, or begins a new one if there is none, it is the default
+	PropagationRequired Propagation = iota
+	// PropagationRequiresNew suspends the current transaction if there is one and always begins a new one
+	PropagationRequiresNew
+	// PropagationSupports joins the current transaction, or runs non-transactionally if there is none
+	PropagationSupports
+	// PropagationNotSupported suspends the current transaction if there is one and runs non-transactionally
+	PropagationNotSupported
+	// PropagationMandatory joins the current transaction, or fails if there is none
+	PropagationMandatory
+	// PropagationNever runs non-transactionally, or fails if there is a current transaction
+	PropagationNever
+	// PropagationNested runs in a nested transaction by a savepoint of the current transaction,
+	// or begins a new one if there is none
+	PropagationNested
+)
+
+var propagationNames = [...]string{
+	"Required", "RequiresNew", "Supports", "NotSupported", "Mandatory", "Never", "Nested",
+}
+
+func (p Propagation) String() string {
+	if p < 0 || int(p) >= len(propagationNames) {
+		return "Unknown"
+	}
+	return propagationNames[p]
+}
+
+// TXManager begins the transaction scopes bound to the returned context,
+// CommitScope and RollbackScope complete the scope bound to the context, only the scope began the transaction
+// commits or rolls back it actually
 type TXManager interface {
-	Begin(ctx context.Context, opts *sql.TxOptions) (newCtx context.Context, err error)
+	Begin(ctx context.Context, opts *sql.TxOptions, beginOpts ...BeginOption) (newCtx context.Context, err error)
+	//Commit commits the transaction returned by TX directly, regardless of the scopes and the hooks
 	Commit(tx any) error
+	//Rollback rolls back the transaction returned by TX directly, regardless of the scopes and the hooks
 	Rollback(tx any) error
+	//CommitScope completes the scope bound to the context by committing
+	CommitScope(ctx context.Context) error
+	//RollbackScope completes the scope bound to the context by rolling back
+	RollbackScope(ctx context.Context) error
+	//IsNewTX returns whether the scope bound to the context began the transaction or the nested one by itself,
+	//false if the scope joined the current transaction or runs non-transactionally
+	IsNewTX(ctx context.Context) bool
+	//RegisterBeforeCommit registers the hook run before the transaction bound to the context is committed,
+	//the error of the hook aborts the commit, error if no transaction is bound
+	RegisterBeforeCommit(ctx context.Context, hook func(ctx context.Context) error) error
+	//RegisterAfterCommit registers the hook run after the transaction bound to the context is committed
+	RegisterAfterCommit(ctx context.Context, hook func(ctx context.Context)) error
+	//RegisterAfterRollback registers the hook run after the transaction bound to the context is rolled back
+	RegisterAfterRollback(ctx context.Context, hook func(ctx context.Context)) error
 	Name() TXName
 	DB() interface{}
 	TX(ctx context.Context) interface{}
 	TXOrDB(ctx context.Context) interface{}
 }
+
+type beginOptions struct {
+	propagation Propagation
+}
+
+type BeginOption func(opts *beginOptions)
+
+// WithPropagation the propagation of Begin, default is PropagationRequired
+func WithPropagation(propagation Propagation) BeginOption {
+	return func(opts *beginOptions) {
+		opts.propagation = propagation
+	}
+}
+
+// InTx runs the fn in the scope begun by the manager, and completes the scope if it began the transaction:
+// commits if the fn returns nil, or rolls back if the fn returns error or panics, the panic is re-panicked after.
+// The scope joined the current transaction is left to the owner, the error or panic of the fn is returned to it
+func InTx(ctx context.Context, manager TXManager, opts *sql.TxOptions, fn func(ctx context.Context) error,
+	beginOpts ...BeginOption) (err error) {
+
+	txCtx, err := manager.Begin(ctx, opts, beginOpts...)
+	if err != nil {
+		return
+	}
+	if !manager.IsNewTX(txCtx) {
+		return fn(txCtx)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			_ = manager.RollbackScope(txCtx)
+			panic(r)
+		}
+	}()
+	if err = fn(txCtx); err != nil {
+		if rollbackErr := manager.RollbackScope(txCtx); rollbackErr != nil {
+			err = fmt.Errorf("%w, rollback fail: %v", err, rollbackErr)
+		}
+		return
+	}
+	return manager.CommitScope(txCtx)
+}
@@ -0,0 +1,116 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"github.com/gomelon/melon/data/engine"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// TXRunner runs the fn in the transaction by InTx, and retries it on the retryable error, such as deadlock.
+// Only the outermost transaction begun by the run is retried, the fn joined the current transaction or
+// nested in it is run once, the failed transaction is left to the owner.
+// The retries are delayed by the exponential backoff with jitter, and stop if the context is done
+// or its deadline is before the next retry
+type TXRunner struct {
+	manager        TXManager
+	classifier     func(err error) bool
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	randMu         sync.Mutex
+	rand           *rand.Rand
+}
+
+// NewTXRunner the retryable errors are classified by the engine, see engine.Engine.IsRetryable
+func NewTXRunner(manager TXManager, engin engine.Engine, opts ...TXRunnerOption) *TXRunner {
+	runner := &TXRunner{
+		manager:        manager,
+		classifier:     engin.IsRetryable,
+		maxAttempts:    3,
+		initialBackoff: 10 * time.Millisecond,
+		maxBackoff:     time.Second,
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, opt := range opts {
+		opt(runner)
+	}
+	return runner
+}
+
+// Run runs the fn by InTx with the options, see InTx, returns the error of the last attempt
+func (r *TXRunner) Run(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error,
+	beginOpts ...BeginOption) (err error) {
+
+	options := &beginOptions{}
+	for _, opt := range beginOpts {
+		opt(options)
+	}
+	//decided before beginning, so the error of beginning the transaction is retried too,
+	//the nested transaction shares the transaction of the context
+	outermost := options.propagation == PropagationRequiresNew || r.manager.TX(ctx) == nil &&
+		(options.propagation == PropagationRequired || options.propagation == PropagationNested)
+
+	for attempt := 1; ; attempt++ {
+		err = InTx(ctx, r.manager, opts, fn, beginOpts...)
+		if err == nil || !outermost || attempt >= r.maxAttempts || !r.classifier(err) {
+			return
+		}
+		backoff := r.backoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before the next attempt, it is doubled per attempt up to the max,
+// and the half of it is jittered
+func (r *TXRunner) backoff(attempt int) time.Duration {
+	backoff := r.initialBackoff
+	for i := 1; i < attempt && backoff < r.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > r.maxBackoff {
+		backoff = r.maxBackoff
+	}
+	half := backoff / 2
+	if half <= 0 {
+		return backoff
+	}
+	r.randMu.Lock()
+	defer r.randMu.Unlock()
+	return half + time.Duration(r.rand.Int63n(int64(half)+1))
+}
+
+type TXRunnerOption func(runner *TXRunner)
+
+// WithTXRunnerClassifier the classifier of the retryable errors, default is engine.Engine.IsRetryable
+func WithTXRunnerClassifier(classifier func(err error) bool) TXRunnerOption {
+	return func(runner *TXRunner) {
+		runner.classifier = classifier
+	}
+}
+
+// WithTXRunnerMaxAttempts the max attempts including the first one, default is 3
+func WithTXRunnerMaxAttempts(maxAttempts int) TXRunnerOption {
+	return func(runner *TXRunner) {
+		runner.maxAttempts = maxAttempts
+	}
+}
+
+// WithTXRunnerBackoff the backoff before the first retry and the max backoff, default is 10ms and 1s
+func WithTXRunnerBackoff(initialBackoff, maxBackoff time.Duration) TXRunnerOption {
+	return func(runner *TXRunner) {
+		runner.initialBackoff = initialBackoff
+		runner.maxBackoff = maxBackoff
+	}
+}
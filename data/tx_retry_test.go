@@ -0,0 +1,134 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"github.com/gomelon/melon/data/engine"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type pgError struct {
+	code string
+}
+
+func (e *pgError) Error() string {
+	return "ERROR: could not serialize access due to concurrent update"
+}
+
+func (e *pgError) SQLState() string {
+	return e.code
+}
+
+func TestTXRunner_Run(t *testing.T) {
+	deadlock := errors.New("Error 1213 (40001): Deadlock found when trying to get lock; try restarting transaction")
+	tests := []struct {
+		name         string
+		engin        engine.Engine
+		outer        bool
+		beginErrs    []error
+		errs         []error
+		timeout      time.Duration
+		wantErr      error
+		wantExecuted []string
+	}{
+		{
+			name:  "retry on deadlock",
+			engin: engine.NewMySQL(),
+			errs:  []error{deadlock},
+			wantExecuted: []string{
+				"BEGIN", "UPDATE", "ROLLBACK",
+				"BEGIN", "UPDATE", "COMMIT",
+			},
+		},
+		{
+			name:  "retry on serialization failure",
+			engin: engine.NewPostgreSQL(),
+			errs:  []error{&pgError{code: "40001"}},
+			wantExecuted: []string{
+				"BEGIN", "UPDATE", "ROLLBACK",
+				"BEGIN", "UPDATE", "COMMIT",
+			},
+		},
+		{
+			name:         "retry on begin failure",
+			engin:        engine.NewMySQL(),
+			beginErrs:    []error{deadlock},
+			wantExecuted: []string{"BEGIN", "BEGIN", "UPDATE", "COMMIT"},
+		},
+		{
+			name:    "max attempts",
+			engin:   engine.NewMySQL(),
+			errs:    []error{deadlock, deadlock, deadlock},
+			wantErr: deadlock,
+			wantExecuted: []string{
+				"BEGIN", "UPDATE", "ROLLBACK",
+				"BEGIN", "UPDATE", "ROLLBACK",
+				"BEGIN", "UPDATE", "ROLLBACK",
+			},
+		},
+		{
+			name:         "not retryable",
+			engin:        engine.NewPostgreSQL(),
+			errs:         []error{deadlock},
+			wantErr:      deadlock,
+			wantExecuted: []string{"BEGIN", "UPDATE", "ROLLBACK"},
+		},
+		{
+			name:         "joined not retried",
+			engin:        engine.NewMySQL(),
+			outer:        true,
+			errs:         []error{deadlock},
+			wantErr:      deadlock,
+			wantExecuted: []string{"BEGIN", "UPDATE"},
+		},
+		{
+			name:         "deadline before retry",
+			engin:        engine.NewMySQL(),
+			errs:         []error{deadlock},
+			timeout:      time.Second,
+			wantErr:      deadlock,
+			wantExecuted: []string{"BEGIN", "UPDATE", "ROLLBACK"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeDB()
+			fake.fails("BEGIN", tt.beginErrs...)
+			fake.fails("UPDATE", tt.errs...)
+			db := fake.open()
+			defer db.Close()
+			tm := NewSqlTxManager("test", db)
+			backoff := time.Millisecond
+			if tt.timeout > 0 {
+				backoff = 2 * tt.timeout
+			}
+			runner := NewTXRunner(tm, tt.engin, WithTXRunnerBackoff(backoff, backoff))
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			if tt.outer {
+				var err error
+				if ctx, err = tm.Begin(ctx, nil); err != nil {
+					t.Fatal(err)
+				}
+				defer tm.RollbackScope(ctx)
+			}
+			err := runner.Run(ctx, nil, func(ctx context.Context) error {
+				_, err := tm.OriginTXOrDB(ctx).ExecContext(ctx, "UPDATE")
+				return err
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if executed := fake.log(); !reflect.DeepEqual(executed, tt.wantExecuted) {
+				t.Errorf("executed \nactual = %v, \nexpect = %v", executed, tt.wantExecuted)
+			}
+		})
+	}
+}
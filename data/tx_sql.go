@@ -3,6 +3,8 @@ package data
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"github.com/gomelon/melon/data/engine"
 )
 
 // SQLExecutor (SQL Go database connection) is a wrapper for SQL database handler ( can be *sql.DB or *sql.Tx)
@@ -68,41 +70,337 @@ type SQLExecutor interface {
 	QueryRow(query string, args ...any) *sql.Row
 }
 
+var _ TXManager = (*SQLTXManager)(nil)
+
 type SQLTXManager struct {
-	name TXName
-	db   *sql.DB
+	name  TXName
+	db    *sql.DB
+	engin engine.Engine
 }
 
-func NewSqlTxManager(name string, db *sql.DB) *SQLTXManager {
-	return &SQLTXManager{
+func NewSqlTxManager(name string, db *sql.DB, opts ...SQLTXManagerOption) *SQLTXManager {
+	tm := &SQLTXManager{
 		name: TXName(name),
 		db:   db,
 	}
+	for _, opt := range opts {
+		opt(tm)
+	}
+	return tm
+}
+
+type SQLTXManagerOption func(tm *SQLTXManager)
+
+// WithSQLTXManagerEngine the engine building the savepoint statements of PropagationNested,
+// the nested transaction is not supported without engine
+func WithSQLTXManagerEngine(engin engine.Engine) SQLTXManagerOption {
+	return func(tm *SQLTXManager) {
+		tm.engin = engin
+	}
+}
+
+// sqlTX is the transaction shared by the scopes joined it,
+// the savepoints of the nested scopes are stacked in order, the hooks are run in the registered order
+// with the outer scope, it is the scope bound to the context when the transaction began
+type sqlTX struct {
+	tx            *sql.Tx
+	outer         *sqlTXScope
+	rollbackOnly  bool
+	completed     bool
+	savepoints    []*sqlSavepoint
+	beforeCommit  []func(ctx context.Context) error
+	afterCommit   []func(ctx context.Context)
+	afterRollback []func(ctx context.Context)
+}
+
+type sqlSavepoint struct {
+	name         string
+	rollbackOnly bool
 }
 
-func (tm *SQLTXManager) Begin(ctx context.Context, opts *sql.TxOptions) (newCtx context.Context, err error) {
-	if ctx.Value(tm.name) != nil {
-		newCtx = ctx
+// markRollbackOnly marks the innermost savepoint, or the transaction if there is no savepoint
+func (tx *sqlTX) markRollbackOnly() {
+	if len(tx.savepoints) > 0 {
+		tx.savepoints[len(tx.savepoints)-1].rollbackOnly = true
 		return
 	}
-	tx, err := tm.db.BeginTx(ctx, opts)
+	tx.rollbackOnly = true
+}
+
+// sqlTXScope is bound to the context returned by Begin, the tx is nil if the scope runs non-transactionally,
+// the savepoint is not nil if the scope is nested in the tx
+type sqlTXScope struct {
+	tx        *sqlTX
+	newTX     bool
+	savepoint *sqlSavepoint
+	completed bool
+}
+
+// Begin begins a scope by the propagation, see Propagation, default is PropagationRequired.
+// The returned context is bound with the scope, complete it by CommitScope or RollbackScope with the returned context.
+// The transaction suspended by PropagationRequiresNew or PropagationNotSupported is still bound to the outer context,
+// and is restored by going on with the outer context after completing the scope
+func (tm *SQLTXManager) Begin(ctx context.Context, opts *sql.TxOptions, beginOpts ...BeginOption,
+) (newCtx context.Context, err error) {
+
+	options := &beginOptions{}
+	for _, opt := range beginOpts {
+		opt(options)
+	}
+	currentTX := tm.currentTX(ctx)
+
+	var scope *sqlTXScope
+	switch options.propagation {
+	case PropagationRequired:
+		if currentTX != nil {
+			scope = &sqlTXScope{tx: currentTX}
+			break
+		}
+		scope, err = tm.beginTX(ctx, opts)
+	case PropagationRequiresNew:
+		scope, err = tm.beginTX(ctx, opts)
+	case PropagationSupports:
+		scope = &sqlTXScope{tx: currentTX}
+	case PropagationNotSupported:
+		scope = &sqlTXScope{}
+	case PropagationMandatory:
+		if currentTX == nil {
+			err = fmt.Errorf("begin [%s] fail: no existing transaction for propagation Mandatory", tm.name)
+			break
+		}
+		scope = &sqlTXScope{tx: currentTX}
+	case PropagationNever:
+		if currentTX != nil {
+			err = fmt.Errorf("begin [%s] fail: existing transaction for propagation Never", tm.name)
+			break
+		}
+		scope = &sqlTXScope{}
+	case PropagationNested:
+		if currentTX != nil {
+			scope, err = tm.beginSavepoint(ctx, currentTX)
+			break
+		}
+		scope, err = tm.beginTX(ctx, opts)
+	default:
+		err = fmt.Errorf("begin [%s] fail: unknown propagation %v", tm.name, options.propagation)
+	}
 	if err != nil {
 		return ctx, err
 	}
-	newCtx = context.WithValue(ctx, tm.name, tx)
+	newCtx = context.WithValue(ctx, tm.name, scope)
 	return
 }
 
+func (tm *SQLTXManager) beginTX(ctx context.Context, opts *sql.TxOptions) (*sqlTXScope, error) {
+	tx, err := tm.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTXScope{tx: &sqlTX{tx: tx, outer: tm.scope(ctx)}, newTX: true}, nil
+}
+
+func (tm *SQLTXManager) beginSavepoint(ctx context.Context, tx *sqlTX) (*sqlTXScope, error) {
+	if tm.engin == nil {
+		return nil, fmt.Errorf("begin [%s] fail: nested transaction requires the engine", tm.name)
+	}
+	savepoint := &sqlSavepoint{name: fmt.Sprintf("melon_savepoint_%d", len(tx.savepoints)+1)}
+	if _, err := tx.tx.ExecContext(ctx, tm.engin.BuildSavepoint(savepoint.name)); err != nil {
+		return nil, err
+	}
+	tx.savepoints = append(tx.savepoints, savepoint)
+	return &sqlTXScope{tx: tx, savepoint: savepoint}, nil
+}
+
+// completeSavepoint pops the savepoint, rolls back to it if rollback, and releases it
+func (tm *SQLTXManager) completeSavepoint(ctx context.Context, scope *sqlTXScope, rollback bool) error {
+	scope.tx.savepoints = scope.tx.savepoints[:len(scope.tx.savepoints)-1]
+	if rollback {
+		if _, err := scope.tx.tx.ExecContext(ctx, tm.engin.BuildRollbackToSavepoint(scope.savepoint.name)); err != nil {
+			return err
+		}
+	}
+	if release := tm.engin.BuildReleaseSavepoint(scope.savepoint.name); len(release) > 0 {
+		if _, err := scope.tx.tx.ExecContext(ctx, release); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit commits the *sql.Tx returned by TX directly, it neither completes the scope nor runs the hooks,
+// use CommitScope to complete the scope bound to the context
 func (tm *SQLTXManager) Commit(tx any) error {
 	sqlTx := tx.(*sql.Tx)
 	return sqlTx.Commit()
 }
 
+// Rollback rolls back the *sql.Tx returned by TX directly, it neither completes the scope nor runs the hooks,
+// use RollbackScope to complete the scope bound to the context
 func (tm *SQLTXManager) Rollback(tx any) error {
 	sqlTx := tx.(*sql.Tx)
 	return sqlTx.Rollback()
 }
 
+// CommitScope completes the scope bound to the context, commits the transaction if the scope began it,
+// or releases the savepoint if the scope is nested,
+// rolls back them instead and returns ErrTXRollbackOnly if they were marked rollback-only
+func (tm *SQLTXManager) CommitScope(ctx context.Context) error {
+	scope, err := tm.complete(ctx)
+	if err != nil || scope.tx == nil {
+		return err
+	}
+	if scope.savepoint != nil {
+		if err = tm.completeSavepoint(ctx, scope, scope.savepoint.rollbackOnly); err != nil {
+			return err
+		}
+		if scope.savepoint.rollbackOnly {
+			return ErrTXRollbackOnly
+		}
+		return nil
+	}
+	if !scope.newTX {
+		return nil
+	}
+	if scope.tx.rollbackOnly {
+		if err = tm.rollback(ctx, scope.tx); err != nil {
+			return err
+		}
+		return ErrTXRollbackOnly
+	}
+	//the hooks registered by the before-commit hooks are run too
+	for i := 0; i < len(scope.tx.beforeCommit); i++ {
+		if err = scope.tx.beforeCommit[i](ctx); err != nil {
+			if rollbackErr := tm.rollback(ctx, scope.tx); rollbackErr != nil {
+				return fmt.Errorf("before commit fail: %w, rollback fail: %v", err, rollbackErr)
+			}
+			return fmt.Errorf("before commit fail: %w", err)
+		}
+	}
+	scope.tx.completed = true
+	if err = scope.tx.tx.Commit(); err != nil {
+		tm.runHooks(ctx, scope.tx, scope.tx.afterRollback)
+		return err
+	}
+	tm.runHooks(ctx, scope.tx, scope.tx.afterCommit)
+	return nil
+}
+
+// RollbackScope completes the scope bound to the context, rolls back the transaction if the scope began it,
+// or rolls back to the savepoint if the scope is nested, or marks the joined transaction rollback-only
+func (tm *SQLTXManager) RollbackScope(ctx context.Context) error {
+	scope, err := tm.complete(ctx)
+	if err != nil || scope.tx == nil {
+		return err
+	}
+	if scope.savepoint != nil {
+		return tm.completeSavepoint(ctx, scope, true)
+	}
+	if !scope.newTX {
+		scope.tx.markRollbackOnly()
+		return nil
+	}
+	return tm.rollback(ctx, scope.tx)
+}
+
+// RegisterBeforeCommit registers the hook run before committing the transaction bound to the context,
+// the hook runs with the transaction, and the error of it aborts the commit and rolls back the transaction
+func (tm *SQLTXManager) RegisterBeforeCommit(ctx context.Context, hook func(ctx context.Context) error) error {
+	tx, err := tm.hookedTX(ctx)
+	if err != nil {
+		return err
+	}
+	tx.beforeCommit = append(tx.beforeCommit, hook)
+	return nil
+}
+
+// RegisterAfterCommit registers the hook run after the transaction bound to the context is committed,
+// the hook runs with the outer scope of the transaction, EX: the transaction suspended by PropagationRequiresNew
+func (tm *SQLTXManager) RegisterAfterCommit(ctx context.Context, hook func(ctx context.Context)) error {
+	tx, err := tm.hookedTX(ctx)
+	if err != nil {
+		return err
+	}
+	tx.afterCommit = append(tx.afterCommit, hook)
+	return nil
+}
+
+// RegisterAfterRollback registers the hook run after the transaction bound to the context is rolled back,
+// or failed to commit, the hook runs with the outer scope of the transaction
+func (tm *SQLTXManager) RegisterAfterRollback(ctx context.Context, hook func(ctx context.Context)) error {
+	tx, err := tm.hookedTX(ctx)
+	if err != nil {
+		return err
+	}
+	tx.afterRollback = append(tx.afterRollback, hook)
+	return nil
+}
+
+func (tm *SQLTXManager) hookedTX(ctx context.Context) (*sqlTX, error) {
+	scope := tm.scope(ctx)
+	if scope == nil || scope.tx == nil {
+		return nil, fmt.Errorf("register hook [%s] fail: no transaction bound to the context", tm.name)
+	}
+	return scope.tx, nil
+}
+
+func (tm *SQLTXManager) rollback(ctx context.Context, tx *sqlTX) error {
+	tx.completed = true
+	err := tx.tx.Rollback()
+	tm.runHooks(ctx, tx, tx.afterRollback)
+	return err
+}
+
+// runHooks runs the after-completion hooks with the context bound to the outer scope of the completed transaction,
+// EX: the transaction suspended by PropagationRequiresNew is restored
+func (tm *SQLTXManager) runHooks(ctx context.Context, tx *sqlTX, hooks []func(ctx context.Context)) {
+	if len(hooks) == 0 {
+		return
+	}
+	ctx = context.WithValue(ctx, tm.name, tx.outer)
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+}
+
+func (tm *SQLTXManager) complete(ctx context.Context) (*sqlTXScope, error) {
+	scope := tm.scope(ctx)
+	if scope == nil {
+		return nil, fmt.Errorf("complete [%s] fail: no transaction scope bound to the context", tm.name)
+	}
+	if scope.completed {
+		return nil, fmt.Errorf("complete [%s] fail: the transaction scope is already completed", tm.name)
+	}
+	if scope.savepoint != nil {
+		savepoints := scope.tx.savepoints
+		if len(savepoints) == 0 || savepoints[len(savepoints)-1] != scope.savepoint {
+			return nil, fmt.Errorf("complete [%s] fail: the inner savepoints of %s are not completed",
+				tm.name, scope.savepoint.name)
+		}
+	}
+	scope.completed = true
+	return scope, nil
+}
+
+func (tm *SQLTXManager) scope(ctx context.Context) *sqlTXScope {
+	scope, _ := ctx.Value(tm.name).(*sqlTXScope)
+	return scope
+}
+
+// currentTX returns the transaction of the scope bound to the context,
+// nil if the scope runs non-transactionally or the transaction is committed or rolled back
+func (tm *SQLTXManager) currentTX(ctx context.Context) *sqlTX {
+	scope := tm.scope(ctx)
+	if scope == nil || scope.tx == nil || scope.tx.completed {
+		return nil
+	}
+	return scope.tx
+}
+
+func (tm *SQLTXManager) IsNewTX(ctx context.Context) bool {
+	scope := tm.scope(ctx)
+	return scope != nil && (scope.newTX || scope.savepoint != nil)
+}
+
 func (tm *SQLTXManager) Name() TXName {
 	return tm.name
 }
@@ -111,8 +409,13 @@ func (tm *SQLTXManager) DB() interface{} {
 	return tm.db
 }
 
+// TX returns the *sql.Tx bound to the context, nil if there is none or it is completed
 func (tm *SQLTXManager) TX(ctx context.Context) interface{} {
-	return ctx.Value(tm.name)
+	tx := tm.currentTX(ctx)
+	if tx == nil {
+		return nil
+	}
+	return tx.tx
 }
 
 func (tm *SQLTXManager) TXOrDB(ctx context.Context) interface{} {
@@ -0,0 +1,398 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gomelon/melon/data/engine"
+	"reflect"
+	"testing"
+)
+
+func TestSQLTXManager_Begin(t *testing.T) {
+	tests := []struct {
+		name         string
+		outer        bool
+		propagation  Propagation
+		rollback     bool
+		wantErr      bool
+		wantTX       bool
+		wantSameTX   bool
+		wantExecuted []string
+	}{
+		{
+			name:         "required without outer",
+			propagation:  PropagationRequired,
+			wantTX:       true,
+			wantExecuted: []string{"BEGIN", "COMMIT"},
+		},
+		{
+			name:         "required joins outer",
+			outer:        true,
+			propagation:  PropagationRequired,
+			wantTX:       true,
+			wantSameTX:   true,
+			wantExecuted: []string{"BEGIN", "COMMIT"},
+		},
+		{
+			name:         "required rollback marks outer rollback-only",
+			outer:        true,
+			propagation:  PropagationRequired,
+			rollback:     true,
+			wantTX:       true,
+			wantSameTX:   true,
+			wantExecuted: []string{"BEGIN", "ROLLBACK"},
+		},
+		{
+			name:         "requires new suspends outer",
+			outer:        true,
+			propagation:  PropagationRequiresNew,
+			rollback:     true,
+			wantTX:       true,
+			wantExecuted: []string{"BEGIN", "BEGIN", "ROLLBACK", "COMMIT"},
+		},
+		{
+			name:         "supports without outer",
+			propagation:  PropagationSupports,
+			wantExecuted: nil,
+		},
+		{
+			name:         "supports joins outer",
+			outer:        true,
+			propagation:  PropagationSupports,
+			wantTX:       true,
+			wantSameTX:   true,
+			wantExecuted: []string{"BEGIN", "COMMIT"},
+		},
+		{
+			name:         "not supported suspends outer",
+			outer:        true,
+			propagation:  PropagationNotSupported,
+			rollback:     true,
+			wantExecuted: []string{"BEGIN", "COMMIT"},
+		},
+		{
+			name:        "mandatory without outer",
+			propagation: PropagationMandatory,
+			wantErr:     true,
+		},
+		{
+			name:         "mandatory joins outer",
+			outer:        true,
+			propagation:  PropagationMandatory,
+			wantTX:       true,
+			wantSameTX:   true,
+			wantExecuted: []string{"BEGIN", "COMMIT"},
+		},
+		{
+			name:         "never with outer",
+			outer:        true,
+			propagation:  PropagationNever,
+			wantErr:      true,
+			wantExecuted: []string{"BEGIN", "COMMIT"},
+		},
+		{
+			name:         "nested without outer",
+			propagation:  PropagationNested,
+			wantTX:       true,
+			wantExecuted: []string{"BEGIN", "COMMIT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeDB()
+			db := fake.open()
+			defer db.Close()
+			tm := NewSqlTxManager("test", db)
+
+			outerCtx := context.Background()
+			var err error
+			if tt.outer {
+				if outerCtx, err = tm.Begin(outerCtx, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			ctx, err := tm.Begin(outerCtx, nil, WithPropagation(tt.propagation))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Begin() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil {
+				if tx := tm.TX(ctx); (tx != nil) != tt.wantTX {
+					t.Errorf("Begin() tx = %v, wantTX %v", tx, tt.wantTX)
+				}
+				if sameTX := tm.TX(ctx) != nil && tm.TX(ctx) == tm.TX(outerCtx); sameTX != tt.wantSameTX {
+					t.Errorf("Begin() same tx = %v, want %v", sameTX, tt.wantSameTX)
+				}
+				if tt.rollback {
+					err = tm.RollbackScope(ctx)
+				} else {
+					err = tm.CommitScope(ctx)
+				}
+				if err != nil {
+					t.Errorf("complete error = %v", err)
+				}
+			}
+			if tt.outer {
+				err = tm.CommitScope(outerCtx)
+				if tt.rollback && tt.wantSameTX {
+					if !errors.Is(err, ErrTXRollbackOnly) {
+						t.Errorf("CommitScope() outer error = %v, want %v", err, ErrTXRollbackOnly)
+					}
+				} else if err != nil {
+					t.Errorf("CommitScope() outer error = %v", err)
+				}
+			}
+			if executed := fake.log(); !reflect.DeepEqual(executed, tt.wantExecuted) {
+				t.Errorf("executed \nactual = %v, \nexpect = %v", executed, tt.wantExecuted)
+			}
+		})
+	}
+}
+
+func TestSQLTXManager_CommitScope(t *testing.T) {
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+	tm := NewSqlTxManager("test", db)
+
+	if err := tm.CommitScope(context.Background()); err == nil {
+		t.Errorf("CommitScope() expected error without transaction scope")
+	}
+	ctx, err := tm.Begin(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tm.OriginTXOrDB(ctx).(*sql.Tx); !ok {
+		t.Errorf("OriginTXOrDB() expected the transaction")
+	}
+	if err = tm.CommitScope(ctx); err != nil {
+		t.Errorf("CommitScope() error = %v", err)
+	}
+	if err = tm.RollbackScope(ctx); err == nil {
+		t.Errorf("RollbackScope() expected error for the completed scope")
+	}
+
+	newCtx, err := tm.Begin(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tm.IsNewTX(newCtx) || tm.TX(newCtx) == tm.TX(ctx) {
+		t.Errorf("Begin() expected the new transaction after the scope is completed")
+	}
+	if err = tm.Commit(tm.TX(newCtx)); err != nil {
+		t.Errorf("Commit() error = %v", err)
+	}
+	if executed := fake.log(); !reflect.DeepEqual(executed, []string{"BEGIN", "COMMIT", "BEGIN", "COMMIT"}) {
+		t.Errorf("executed \nactual = %v, \nexpect = %v", executed, []string{"BEGIN", "COMMIT", "BEGIN", "COMMIT"})
+	}
+}
+
+func TestSQLTXManager_Nested(t *testing.T) {
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+	tm := NewSqlTxManager("test", db, WithSQLTXManagerEngine(engine.NewMySQL()))
+
+	outerCtx, err := tm.Begin(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nestedCtx, err := tm.Begin(outerCtx, nil, WithPropagation(PropagationNested))
+	if err != nil {
+		t.Fatal(err)
+	}
+	innerCtx, err := tm.Begin(nestedCtx, nil, WithPropagation(PropagationNested))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tm.CommitScope(nestedCtx); err == nil {
+		t.Errorf("CommitScope() expected error for the uncompleted inner savepoint")
+	}
+	if err = tm.RollbackScope(innerCtx); err != nil {
+		t.Errorf("RollbackScope() inner error = %v", err)
+	}
+	if err = tm.CommitScope(nestedCtx); err != nil {
+		t.Errorf("CommitScope() nested error = %v", err)
+	}
+
+	nestedCtx, err = tm.Begin(outerCtx, nil, WithPropagation(PropagationNested))
+	if err != nil {
+		t.Fatal(err)
+	}
+	joinedCtx, err := tm.Begin(nestedCtx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tm.RollbackScope(joinedCtx); err != nil {
+		t.Errorf("RollbackScope() joined error = %v", err)
+	}
+	if err = tm.CommitScope(nestedCtx); !errors.Is(err, ErrTXRollbackOnly) {
+		t.Errorf("CommitScope() nested error = %v, want %v", err, ErrTXRollbackOnly)
+	}
+	if err = tm.CommitScope(outerCtx); err != nil {
+		t.Errorf("CommitScope() outer error = %v", err)
+	}
+
+	wantExecuted := []string{
+		"BEGIN",
+		"SAVEPOINT melon_savepoint_1",
+		"SAVEPOINT melon_savepoint_2",
+		"ROLLBACK TO SAVEPOINT melon_savepoint_2",
+		"RELEASE SAVEPOINT melon_savepoint_2",
+		"RELEASE SAVEPOINT melon_savepoint_1",
+		"SAVEPOINT melon_savepoint_1",
+		"ROLLBACK TO SAVEPOINT melon_savepoint_1",
+		"RELEASE SAVEPOINT melon_savepoint_1",
+		"COMMIT",
+	}
+	if executed := fake.log(); !reflect.DeepEqual(executed, wantExecuted) {
+		t.Errorf("executed \nactual = %v, \nexpect = %v", executed, wantExecuted)
+	}
+
+	tm = NewSqlTxManager("test", db)
+	outerCtx, err = tm.Begin(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tm.RollbackScope(outerCtx)
+	if _, err = tm.Begin(outerCtx, nil, WithPropagation(PropagationNested)); err == nil {
+		t.Errorf("Begin() expected error for the nested transaction without engine")
+	}
+}
+
+func TestSQLTXManager_Hooks(t *testing.T) {
+	errFail := errors.New("fail")
+	tests := []struct {
+		name         string
+		beforeErr    error
+		rollback     bool
+		wantErr      error
+		wantCalled   []string
+		wantExecuted []string
+	}{
+		{
+			name:         "commit",
+			wantCalled:   []string{"before 1", "before 2", "after commit 1", "after commit 2"},
+			wantExecuted: []string{"BEGIN", "COMMIT"},
+		},
+		{
+			name:         "before commit fail",
+			beforeErr:    errFail,
+			wantErr:      errFail,
+			wantCalled:   []string{"before 1", "after rollback"},
+			wantExecuted: []string{"BEGIN", "ROLLBACK"},
+		},
+		{
+			name:         "rollback",
+			rollback:     true,
+			wantCalled:   []string{"after rollback"},
+			wantExecuted: []string{"BEGIN", "ROLLBACK"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeDB()
+			db := fake.open()
+			defer db.Close()
+			tm := NewSqlTxManager("test", db)
+
+			if err := tm.RegisterAfterCommit(context.Background(), func(ctx context.Context) {}); err == nil {
+				t.Errorf("RegisterAfterCommit() expected error without transaction")
+			}
+			outerCtx, err := tm.Begin(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			joinedCtx, err := tm.Begin(outerCtx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var called []string
+			_ = tm.RegisterBeforeCommit(joinedCtx, func(ctx context.Context) error {
+				called = append(called, "before 1")
+				if tm.TX(ctx) == nil {
+					t.Errorf("before commit hook expected the transaction")
+				}
+				return tt.beforeErr
+			})
+			_ = tm.RegisterBeforeCommit(outerCtx, func(ctx context.Context) error {
+				called = append(called, "before 2")
+				return nil
+			})
+			_ = tm.RegisterAfterCommit(joinedCtx, func(ctx context.Context) {
+				called = append(called, "after commit 1")
+				if tm.TX(ctx) != nil {
+					t.Errorf("after commit hook expected no transaction")
+				}
+			})
+			_ = tm.RegisterAfterCommit(outerCtx, func(ctx context.Context) {
+				called = append(called, "after commit 2")
+			})
+			_ = tm.RegisterAfterRollback(joinedCtx, func(ctx context.Context) {
+				called = append(called, "after rollback")
+			})
+
+			if err = tm.CommitScope(joinedCtx); err != nil {
+				t.Errorf("CommitScope() joined error = %v", err)
+			}
+			if len(called) > 0 {
+				t.Errorf("hooks called before the outermost transaction completed: %v", called)
+			}
+			if tt.rollback {
+				err = tm.RollbackScope(outerCtx)
+			} else {
+				err = tm.CommitScope(outerCtx)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("complete error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(called, tt.wantCalled) {
+				t.Errorf("called \nactual = %v, \nexpect = %v", called, tt.wantCalled)
+			}
+			if executed := fake.log(); !reflect.DeepEqual(executed, tt.wantExecuted) {
+				t.Errorf("executed \nactual = %v, \nexpect = %v", executed, tt.wantExecuted)
+			}
+		})
+	}
+}
+
+func TestSQLTXManager_Hooks_RequiresNew(t *testing.T) {
+	fake := newFakeDB()
+	db := fake.open()
+	defer db.Close()
+	tm := NewSqlTxManager("test", db)
+
+	outerCtx, err := tm.Begin(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	innerCtx, err := tm.Begin(outerCtx, nil, WithPropagation(PropagationRequiresNew))
+	if err != nil {
+		t.Fatal(err)
+	}
+	called := false
+	_ = tm.RegisterAfterCommit(innerCtx, func(ctx context.Context) {
+		called = true
+		if tx := tm.TX(ctx); tx == nil || tx != tm.TX(outerCtx) {
+			t.Errorf("after commit hook expected the suspended outer transaction, actual %v", tx)
+		}
+		if err := tm.RegisterAfterCommit(ctx, func(ctx context.Context) {}); err != nil {
+			t.Errorf("RegisterAfterCommit() in the hook error = %v", err)
+		}
+	})
+	if err = tm.CommitScope(innerCtx); err != nil {
+		t.Errorf("CommitScope() inner error = %v", err)
+	}
+	if !called {
+		t.Errorf("after commit hook is not called")
+	}
+	if err = tm.CommitScope(outerCtx); err != nil {
+		t.Errorf("CommitScope() outer error = %v", err)
+	}
+	wantExecuted := []string{"BEGIN", "BEGIN", "COMMIT", "COMMIT"}
+	if executed := fake.log(); !reflect.DeepEqual(executed, wantExecuted) {
+		t.Errorf("executed \nactual = %v, \nexpect = %v", executed, wantExecuted)
+	}
+}
@@ -0,0 +1,83 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInTx(t *testing.T) {
+	errFail := errors.New("fail")
+	tests := []struct {
+		name         string
+		outer        bool
+		fn           func(ctx context.Context) error
+		wantErr      error
+		wantPanic    bool
+		wantExecuted []string
+	}{
+		{
+			name:         "commit",
+			fn:           func(ctx context.Context) error { return nil },
+			wantExecuted: []string{"BEGIN", "COMMIT"},
+		},
+		{
+			name:         "rollback on error",
+			fn:           func(ctx context.Context) error { return errFail },
+			wantErr:      errFail,
+			wantExecuted: []string{"BEGIN", "ROLLBACK"},
+		},
+		{
+			name:         "rollback on panic",
+			fn:           func(ctx context.Context) error { panic("fail") },
+			wantPanic:    true,
+			wantExecuted: []string{"BEGIN", "ROLLBACK"},
+		},
+		{
+			name:         "joined leaves outer",
+			outer:        true,
+			fn:           func(ctx context.Context) error { return errFail },
+			wantErr:      errFail,
+			wantExecuted: []string{"BEGIN"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeDB()
+			db := fake.open()
+			defer db.Close()
+			tm := NewSqlTxManager("test", db)
+
+			ctx := context.Background()
+			if tt.outer {
+				var err error
+				if ctx, err = tm.Begin(ctx, nil); err != nil {
+					t.Fatal(err)
+				}
+				if !tm.IsNewTX(ctx) {
+					t.Errorf("IsNewTX() = false, want true for the outer scope")
+				}
+			}
+			func() {
+				defer func() {
+					if r := recover(); (r != nil) != tt.wantPanic {
+						t.Errorf("InTx() panic = %v, wantPanic %v", r, tt.wantPanic)
+					}
+				}()
+				err := InTx(ctx, tm, nil, func(ctx context.Context) error {
+					if tm.IsNewTX(ctx) == tt.outer {
+						t.Errorf("IsNewTX() = %v, want %v", tm.IsNewTX(ctx), !tt.outer)
+					}
+					return tt.fn(ctx)
+				})
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("InTx() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}()
+			if executed := fake.log(); !reflect.DeepEqual(executed, tt.wantExecuted) {
+				t.Errorf("executed \nactual = %v, \nexpect = %v", executed, tt.wantExecuted)
+			}
+		})
+	}
+}